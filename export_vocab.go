@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"textreader/internal/export"
+	"textreader/internal/file"
+)
+
+// runExportVocab writes the vocabulary of every book in the progress file, or of the books
+// matching --book, as CSV, TSV or Anki notes.
+func runExportVocab(args []string, w io.Writer, cfg export.Config) error {
+	flags := flag.NewFlagSet("export-vocab", flag.ContinueOnError)
+	formatFlag := flags.String("format", export.FormatCSV, "Format of the export: csv, tsv or anki")
+	bookFlag := flags.String("book", "", "Export only the books whose title or file name contains this text")
+	outputFlag := flags.String("output", "", "File to write, standard output by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if _, err := export.Extension(*formatFlag); err != nil {
+		return err
+	}
+
+	entries, err := file.LoadProgress()
+	if err != nil {
+		return fmt.Errorf("failed to load progress: %w", err)
+	}
+	words := export.Vocabulary(entries, *bookFlag)
+	if len(words) == 0 {
+		return fmt.Errorf("no vocabulary to export")
+	}
+
+	if *outputFlag != "" {
+		f, err := os.Create(*outputFlag)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *outputFlag, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return export.Write(w, *formatFlag, words, cfg)
+}
@@ -7,6 +7,7 @@ toolchain go1.24.4
 require (
 	github.com/atotto/clipboard v0.1.4
 	github.com/marcusolsson/tui-go v0.4.0
+	golang.org/x/term v0.35.0
 )
 
 require (
@@ -17,6 +18,5 @@ require (
 	github.com/mitchellh/go-wordwrap v1.0.1 // indirect
 	github.com/rivo/uniseg v0.4.4 // indirect
 	golang.org/x/sys v0.36.0 // indirect
-	golang.org/x/term v0.35.0 // indirect
 	golang.org/x/text v0.11.0 // indirect
 )
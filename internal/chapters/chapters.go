@@ -0,0 +1,162 @@
+package chapters
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"textreader/internal/model"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rules tunes how chapter headings are recognized in plain text. The zero value uses the
+// default keywords and every heuristic.
+type Rules struct {
+	// Keywords start a heading when followed by a number or standing alone on their line,
+	// e.g. "CAPÍTULO 3" or "Chapter XII. The Return". They are compared ignoring case.
+	Keywords []string `json:"keywords,omitempty"`
+	// SkipRomanNumerals stops lines holding just a Roman numeral ("XIV.") from being headings.
+	SkipRomanNumerals bool `json:"skip_roman_numerals,omitempty"`
+	// SkipAllCaps stops short all-caps lines surrounded by blank lines from being headings.
+	SkipAllCaps bool `json:"skip_all_caps,omitempty"`
+	// MaxTitleLength is the longest line taken as a heading, 40 characters by default.
+	MaxTitleLength int `json:"max_title_length,omitempty"`
+}
+
+// DefaultKeywords are used when Rules.Keywords is empty.
+var DefaultKeywords = []string{
+	"capítulo", "capitulo", "chapter", "chapitre", "kapitel", "parte", "part", "libro", "book",
+	"prólogo", "prologo", "prologue", "epílogo", "epilogo", "epilogue",
+}
+
+const defaultMaxTitleLength = 40
+
+var (
+	romanRe  = regexp.MustCompile(`^M{0,3}(CM|CD|D?C{0,3})(XC|XL|L?X{0,3})(IX|IV|V?I{0,3})$`)
+	numberRe = regexp.MustCompile(`^\d+\b`)
+	spacesRe = regexp.MustCompile(`\s+`)
+)
+
+// Detect finds chapter headings in lines[start:end], for books whose format doesn't tell
+// where chapters begin.
+func Detect(lines []string, start, end int, rules Rules) []model.Chapter {
+	keywords := rules.Keywords
+	if len(keywords) == 0 {
+		keywords = DefaultKeywords
+	}
+	maxLength := rules.MaxTitleLength
+	if maxLength <= 0 {
+		maxLength = defaultMaxTitleLength
+	}
+
+	blank := func(i int) bool {
+		return i < start || i >= end || strings.TrimSpace(lines[i]) == ""
+	}
+	short := func(s string) bool {
+		return s != "" && utf8.RuneCountInString(s) <= maxLength
+	}
+
+	chapters := make([]model.Chapter, 0)
+	for i := start; i < end; i++ {
+		line := title(lines[i])
+		if !short(line) || !blank(i-1) {
+			continue
+		}
+
+		numbered := false
+		heading := false
+		if rest, ok := afterKeyword(line, keywords); ok {
+			numbered = numberRe.MatchString(rest) || roman(strings.ToUpper(firstField(rest)))
+			heading = numbered || blank(i+1)
+		} else if !rules.SkipRomanNumerals && roman(line) && blank(i+1) {
+			numbered, heading = true, true
+		} else if !rules.SkipAllCaps && allCaps(line) && blank(i+1) {
+			heading = true
+		}
+		if !heading {
+			continue
+		}
+
+		// "CHAPTER I." followed by its name on a line of its own: "CHAPTER I. Loomings".
+		if next := i + 2; numbered && len(strings.Fields(line)) <= 2 && blank(i+1) && next < end && blank(next+1) {
+			if name := title(lines[next]); short(name) {
+				if _, ok := afterKeyword(name, keywords); !ok && !roman(name) {
+					chapters = append(chapters, model.Chapter{Title: line + " " + name, Line: i})
+					i = next
+					continue
+				}
+			}
+		}
+		chapters = append(chapters, model.Chapter{Title: line, Line: i})
+	}
+	return chapters
+}
+
+// afterKeyword returns what follows a heading keyword at the start of line, in lower case.
+func afterKeyword(line string, keywords []string) (string, bool) {
+	lower := strings.ToLower(line)
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		if !strings.HasPrefix(lower, keyword) {
+			continue
+		}
+		rest := lower[len(keyword):]
+		if r, _ := utf8.DecodeRuneInString(rest); rest != "" && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			continue
+		}
+		return strings.TrimLeftFunc(rest, func(r rune) bool {
+			return unicode.IsSpace(r) || r == ':' || r == '.'
+		}), true
+	}
+	return "", false
+}
+
+func roman(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	return s != "" && romanRe.MatchString(s)
+}
+
+func firstField(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return strings.TrimRight(fields[0], ".:")
+	}
+	return ""
+}
+
+func allCaps(line string) bool {
+	letters := 0
+	for _, r := range line {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 2
+}
+
+func title(line string) string {
+	return spacesRe.ReplaceAllString(strings.TrimSpace(line), " ")
+}
+
+// Current returns the index of the chapter line belongs to, or -1 before the first chapter.
+func Current(chapters []model.Chapter, line int) int {
+	return sort.Search(len(chapters), func(i int) bool { return chapters[i].Line > line }) - 1
+}
+
+// Percentage tells how far line is through the i-th chapter, which ends where the next one
+// starts or at end.
+func Percentage(chapters []model.Chapter, i, line, end int) float64 {
+	if i < 0 || i >= len(chapters) {
+		return 0
+	}
+	if i+1 < len(chapters) {
+		end = chapters[i+1].Line
+	}
+	length := end - chapters[i].Line
+	if length <= 0 {
+		return 100
+	}
+	return min(100, float64(line-chapters[i].Line)*100/float64(length))
+}
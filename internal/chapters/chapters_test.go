@@ -0,0 +1,83 @@
+package chapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"textreader/internal/model"
+)
+
+func TestDetect(t *testing.T) {
+	book := strings.Split(`Produced by volunteers.
+
+CHAPTER I.
+
+Loomings
+
+Call me Ishmael. Some years ago, never mind how long precisely, having little
+or no money in my purse.
+Chapter 2 was mentioned in the middle of a paragraph.
+
+CAPÍTULO 2: La partida
+
+Part of the crew was asleep, and the
+rest of it was not.
+
+XIV.
+
+The whale was seen.
+
+THE CARPET-BAG
+
+I stuffed a shirt or two into my old carpet-bag.
+
+Epílogo
+
+Fin.`, "\n")
+
+	got := Detect(book, 2, len(book), Rules{})
+	want := []model.Chapter{
+		{Title: "CHAPTER I. Loomings", Line: 2},
+		{Title: "CAPÍTULO 2: La partida", Line: 10},
+		{Title: "XIV. The whale was seen.", Line: 15},
+		{Title: "THE CARPET-BAG", Line: 19},
+		{Title: "Epílogo", Line: 23},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Detect() = %v, want %v", got, want)
+	}
+
+	got = Detect(book, 2, len(book), Rules{Keywords: []string{"chapter"}, SkipRomanNumerals: true, SkipAllCaps: true})
+	want = []model.Chapter{{Title: "CHAPTER I. Loomings", Line: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Detect(custom rules) = %v, want %v", got, want)
+	}
+}
+
+func TestCurrentAndPercentage(t *testing.T) {
+	chapters := []model.Chapter{{Title: "One", Line: 10}, {Title: "Two", Line: 30}}
+
+	type test struct {
+		line        int
+		wantCurrent int
+		wantPercent float64
+	}
+
+	tests := []test{
+		{line: 0, wantCurrent: -1, wantPercent: 0},
+		{line: 10, wantCurrent: 0, wantPercent: 0},
+		{line: 20, wantCurrent: 0, wantPercent: 50},
+		{line: 30, wantCurrent: 1, wantPercent: 0},
+		{line: 45, wantCurrent: 1, wantPercent: 75},
+	}
+
+	for _, tc := range tests {
+		current := Current(chapters, tc.line)
+		if current != tc.wantCurrent {
+			t.Errorf("Current(%d) = %d, want %d", tc.line, current, tc.wantCurrent)
+		}
+		if got := Percentage(chapters, current, tc.line, 50); got != tc.wantPercent {
+			t.Errorf("Percentage(%d) = %v, want %v", tc.line, got, tc.wantPercent)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"textreader/internal/chapters"
+	"textreader/internal/dictionary"
+	"textreader/internal/export"
+	"textreader/internal/file"
+	"textreader/internal/model"
+	"textreader/internal/stats"
+)
+
+// Converter reads a format the reader does not know by running an external command, e.g.
+// {"name": "mobi", "extensions": [".mobi"], "command": ["ebook-convert", "{file}", "/dev/stdout"]}.
+// See file.CommandLoader for how the book is handed to the command.
+type Converter struct {
+	Name       string   `json:"name"`
+	Extensions []string `json:"extensions"`
+	Command    []string `json:"command"`
+	// Output is the format the command writes, "text" by default.
+	Output string `json:"output,omitempty"`
+}
+
+// Config holds the user settings of ~/ltbr/config.json.
+type Config struct {
+	Converters []Converter `json:"converters,omitempty"`
+	// Chapters tunes the chapter detection of books that don't mark their chapters.
+	Chapters chapters.Rules `json:"chapters,omitempty"`
+	// Goal is how much to read every day, in minutes, lines or percentage points.
+	Goal model.Goal `json:"goal,omitempty"`
+	// Dictionaries tells where the StarDict and dictd dictionaries are, ~/ltbr/dictionaries
+	// by default.
+	Dictionaries dictionary.Config `json:"dictionaries,omitempty"`
+	// DictServer is the DICT server words are looked up in, when there is one.
+	DictServer dictionary.ServerConfig `json:"dict_server,omitempty"`
+	// VocabExport tells how the vocabulary is exported, e.g. the fields and tags of Anki notes.
+	VocabExport export.Config `json:"vocab_export,omitempty"`
+}
+
+// Path returns where the configuration file lives.
+func Path() string {
+	return filepath.Join(file.GetHomeDirectoryPath(runtime.GOOS), "ltbr", "config.json")
+}
+
+// DictionariesDir returns where the dictionaries are looked for.
+func (c Config) DictionariesDir() string {
+	if c.Dictionaries.Dir != "" {
+		return c.Dictionaries.Dir
+	}
+	return filepath.Join(file.GetHomeDirectoryPath(runtime.GOOS), "ltbr", "dictionaries")
+}
+
+// Load reads the configuration at path. A missing file is an empty configuration.
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := stats.ValidateGoal(cfg.Goal); err != nil {
+		return cfg, fmt.Errorf("invalid goal in config file %s: %w", path, err)
+	}
+	if err := export.Validate(cfg.VocabExport); err != nil {
+		return cfg, fmt.Errorf("invalid vocabulary export in config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// RegisterConverters makes the configured converters available as document formats of registry.
+func RegisterConverters(registry *file.Registry, converters []Converter) error {
+	for _, c := range converters {
+		if c.Name == "" || len(c.Command) == 0 {
+			return fmt.Errorf("converter %q needs a name and a command", c.Name)
+		}
+		loader := file.CommandLoader{Command: c.Command, Output: c.Output}
+		if len(c.Extensions) > 0 {
+			loader.Extension = c.Extensions[0]
+		}
+		registry.Register(file.Format{Name: c.Name, Extensions: c.Extensions, Loader: loader})
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"textreader/internal/chapters"
+	"textreader/internal/dictionary"
+	"textreader/internal/export"
+	"textreader/internal/file"
+	"textreader/internal/model"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || !reflect.DeepEqual(cfg, Config{}) {
+		t.Errorf("got=[%v, %v], want=[an empty config]", cfg, err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	content := `{"converters": [{"name": "mobi", "extensions": [".mobi"], "command": ["ebook-convert", "{file}", "/dev/stdout"]}],
+		"chapters": {"keywords": ["capítulo"], "skip_all_caps": true},
+		"goal": {"unit": "minutes", "amount": 30},
+		"dictionaries": {"dir": "/usr/share/stardict/dic", "priority": ["rae"]},
+		"dict_server": {"address": "dict.lan", "database": "fd-eng-spa", "strategy": "prefix"},
+		"vocab_export": {"format": "tsv", "fields": ["word", "note"], "tags": ["{book}"], "deck": "Español"}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Converter{{Name: "mobi", Extensions: []string{".mobi"}, Command: []string{"ebook-convert", "{file}", "/dev/stdout"}}}
+	if !reflect.DeepEqual(cfg.Converters, want) {
+		t.Errorf("got=[%v], want=[%v]", cfg.Converters, want)
+	}
+
+	if want := (chapters.Rules{Keywords: []string{"capítulo"}, SkipAllCaps: true}); !reflect.DeepEqual(cfg.Chapters, want) {
+		t.Errorf("got=[%v], want=[%v]", cfg.Chapters, want)
+	}
+
+	if want := (model.Goal{Unit: model.GoalMinutes, Amount: 30}); cfg.Goal != want {
+		t.Errorf("got=[%v], want=[%v]", cfg.Goal, want)
+	}
+
+	if dir := cfg.DictionariesDir(); dir != "/usr/share/stardict/dic" || !reflect.DeepEqual(cfg.Dictionaries.Priority, []string{"rae"}) {
+		t.Errorf("got=[%v], want=[/usr/share/stardict/dic with rae first]", cfg.Dictionaries)
+	}
+	if want := (dictionary.ServerConfig{Address: "dict.lan", Database: "fd-eng-spa", Strategy: "prefix"}); cfg.DictServer != want {
+		t.Errorf("got=[%v], want=[%v]", cfg.DictServer, want)
+	}
+	wantExport := export.Config{Format: "tsv", Fields: []string{"word", "note"}, Tags: []string{"{book}"}, Deck: "Español"}
+	if !reflect.DeepEqual(cfg.VocabExport, wantExport) {
+		t.Errorf("got=[%v], want=[%v]", cfg.VocabExport, wantExport)
+	}
+	if dir := (Config{}).DictionariesDir(); filepath.Base(dir) != "dictionaries" {
+		t.Errorf("got=[%s], want=[~/ltbr/dictionaries]", dir)
+	}
+
+	registry := file.NewRegistry()
+	if err := RegisterConverters(registry, cfg.Converters); err != nil {
+		t.Fatal(err)
+	}
+	format, err := registry.Find("book.mobi", "", nil)
+	if err != nil || format.Name != "mobi" {
+		t.Errorf("got=[%s, %v], want=[mobi]", format.Name, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"goal": {"unit": "pages", "amount": 10}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() expected an error for an unknown goal unit")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"vocab_export": {"fields": ["word", "meaning"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() expected an error for an unknown export field")
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() expected an error for invalid JSON")
+	}
+}
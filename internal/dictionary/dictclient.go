@@ -0,0 +1,193 @@
+package dictionary
+
+import (
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// DefaultPort is the port of DICT servers.
+const DefaultPort = "2628"
+
+// ServerConfig tells which DICT server (RFC 2229) to ask, e.g.
+// {"address": "dict.lan", "database": "fd-eng-spa", "strategy": "prefix"}.
+type ServerConfig struct {
+	// Address is host or host:port.
+	Address string `json:"address"`
+	// Database is where words are looked up, "*" (every database) by default or "!" for the
+	// first one with the word.
+	Database string `json:"database,omitempty"`
+	// Strategy is how MATCH finds similar words, "." (the default of the server) by default.
+	Strategy string `json:"strategy,omitempty"`
+	// TimeoutSeconds bounds the whole conversation with the server, 10 seconds by default.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// Match is a word of a database that looks like the one looked up.
+type Match struct {
+	Database, Word string
+}
+
+// Client talks to a DICT server.
+type Client struct {
+	conn *textproto.Conn
+}
+
+// Dial connects to the server of cfg.
+func Dial(cfg ServerConfig) (*Client, error) {
+	address := cfg.Address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultPort)
+	}
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &Client{conn: textproto.NewConn(conn)}
+	if _, _, err := c.conn.ReadCodeLine(220); err != nil {
+		c.conn.Close()
+		return nil, fmt.Errorf("failed to greet %s: %w", address, err)
+	}
+	return c, nil
+}
+
+// Close says goodbye to the server and closes the connection.
+func (c *Client) Close() error {
+	if _, err := c.conn.Cmd("QUIT"); err == nil {
+		_, _, _ = c.conn.ReadCodeLine(221)
+	}
+	return c.conn.Close()
+}
+
+// Define returns the definitions of word in database, none when the server has none.
+func (c *Client) Define(database, word string) ([]Definition, error) {
+	id, err := c.conn.Cmd("DEFINE %s %s", quote(database), quote(word))
+	if err != nil {
+		return nil, err
+	}
+	c.conn.StartResponse(id)
+	defer c.conn.EndResponse(id)
+
+	code, msg, err := c.conn.ReadCodeLine(150)
+	if code == 552 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to define %q: %w", word, err)
+	}
+	var definitions []Definition
+	for range count(msg) {
+		_, header, err := c.conn.ReadCodeLine(151)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the definition of %q: %w", word, err)
+		}
+		lines, err := c.conn.ReadDotLines()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the definition of %q: %w", word, err)
+		}
+		definitions = append(definitions, Definition{Dictionary: databaseName(header), Text: strings.TrimSpace(strings.Join(lines, "\n"))})
+	}
+	if _, _, err := c.conn.ReadCodeLine(250); err != nil {
+		return nil, fmt.Errorf("failed to define %q: %w", word, err)
+	}
+	return definitions, nil
+}
+
+// Match returns the words of database that look like word according to strategy.
+func (c *Client) Match(database, strategy, word string) ([]Match, error) {
+	id, err := c.conn.Cmd("MATCH %s %s %s", quote(database), quote(strategy), quote(word))
+	if err != nil {
+		return nil, err
+	}
+	c.conn.StartResponse(id)
+	defer c.conn.EndResponse(id)
+
+	code, _, err := c.conn.ReadCodeLine(152)
+	if code == 552 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to match %q: %w", word, err)
+	}
+	lines, err := c.conn.ReadDotLines()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the matches of %q: %w", word, err)
+	}
+	var matches []Match
+	for _, line := range lines {
+		database, word, ok := strings.Cut(line, " ")
+		if ok {
+			matches = append(matches, Match{Database: database, Word: strings.Trim(word, `"`)})
+		}
+	}
+	if _, _, err := c.conn.ReadCodeLine(250); err != nil {
+		return nil, fmt.Errorf("failed to match %q: %w", word, err)
+	}
+	return matches, nil
+}
+
+// Lookup defines word on the server of cfg and, when it has no definitions, finds the words
+// that look like it.
+func Lookup(cfg ServerConfig, word string) ([]Definition, []Match, error) {
+	database, strategy := cfg.Database, cfg.Strategy
+	if database == "" {
+		database = "*"
+	}
+	if strategy == "" {
+		strategy = "."
+	}
+
+	c, err := Dial(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.Close()
+
+	definitions, err := c.Define(database, word)
+	if err != nil || len(definitions) > 0 {
+		return definitions, nil, err
+	}
+	matches, err := c.Match(database, strategy, word)
+	return nil, matches, err
+}
+
+// quote writes an argument of a command, in quotes when it has spaces or quotes.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// count reads the number a status line such as "2 definitions retrieved" starts with.
+func count(msg string) int {
+	n := 0
+	fmt.Sscanf(msg, "%d", &n)
+	return n
+}
+
+// databaseName reads the database of a 151 status line, `"word" database "description"`,
+// preferring the description.
+func databaseName(header string) string {
+	fields := strings.SplitN(header, `"`, 5)
+	if len(fields) == 5 && strings.TrimSpace(fields[3]) != "" {
+		return fields[3]
+	}
+	if len(fields) >= 3 {
+		if database, _, _ := strings.Cut(strings.TrimSpace(fields[2]), " "); database != "" {
+			return database
+		}
+	}
+	return header
+}
@@ -0,0 +1,106 @@
+package dictionary
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeServer answers DICT commands with the replies for them, and 552 to the rest. It
+// returns the address it listens on and the commands it received, once the client quits.
+func fakeServer(t *testing.T, replies map[string]string) (string, <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var commands []string
+		defer func() { received <- commands }()
+
+		w := bufio.NewWriter(conn)
+		w.WriteString("220 fake dictd <auth.mime> <1.2@fake>\r\n")
+		w.Flush()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			command := scanner.Text()
+			commands = append(commands, command)
+			switch reply, ok := replies[command]; {
+			case command == "QUIT":
+				w.WriteString("221 bye\r\n")
+				w.Flush()
+				return
+			case ok:
+				w.WriteString(strings.ReplaceAll(reply, "\n", "\r\n"))
+			default:
+				w.WriteString("552 no match\r\n")
+			}
+			w.Flush()
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestLookupDefines(t *testing.T) {
+	address, received := fakeServer(t, map[string]string{
+		`DEFINE * "rocín flaco"`: "150 2 definitions retrieved\n" +
+			`151 "rocín flaco" rae "Diccionario de la lengua española"` + "\n" +
+			"Caballo de trabajo,\n..de mala traza.\n.\n" +
+			`151 "rocín flaco" sinonimos ""` + "\n" +
+			"jamelgo\n.\n" +
+			"250 ok\n",
+	})
+
+	definitions, matches, err := Lookup(ServerConfig{Address: address}, "rocín flaco")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Definition{
+		{Dictionary: "Diccionario de la lengua española", Text: "Caballo de trabajo,\n.de mala traza."},
+		{Dictionary: "sinonimos", Text: "jamelgo"},
+	}
+	if !reflect.DeepEqual(definitions, want) || matches != nil {
+		t.Errorf("Lookup() = %q, %q, want %q", definitions, matches, want)
+	}
+	if got := <-received; !reflect.DeepEqual(got, []string{`DEFINE * "rocín flaco"`, "QUIT"}) {
+		t.Errorf("server received %q", got)
+	}
+}
+
+func TestLookupMatches(t *testing.T) {
+	address, received := fakeServer(t, map[string]string{
+		"MATCH rae prefix hidalg": "152 2 matches found\n" +
+			"rae \"hidalgo\"\nrae \"hidalguía\"\n.\n" +
+			"250 ok\n",
+	})
+
+	cfg := ServerConfig{Address: address, Database: "rae", Strategy: "prefix"}
+	definitions, matches, err := Lookup(cfg, "hidalg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Match{{Database: "rae", Word: "hidalgo"}, {Database: "rae", Word: "hidalguía"}}
+	if definitions != nil || !reflect.DeepEqual(matches, want) {
+		t.Errorf("Lookup() = %q, %q, want matches %q", definitions, matches, want)
+	}
+	if got := <-received; !reflect.DeepEqual(got, []string{"DEFINE rae hidalg", "MATCH rae prefix hidalg", "QUIT"}) {
+		t.Errorf("server received %q", got)
+	}
+}
+
+func TestLookupInvalidDatabase(t *testing.T) {
+	address, _ := fakeServer(t, map[string]string{"DEFINE nada hidalgo": "550 invalid database\n"})
+	if _, _, err := Lookup(ServerConfig{Address: address, Database: "nada"}, "hidalgo"); err == nil {
+		t.Error("Lookup() expected an error for an invalid database")
+	}
+}
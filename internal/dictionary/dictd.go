@@ -0,0 +1,95 @@
+package dictionary
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// dictdDigits are the digits of the base64 numbers of dictd indexes.
+const dictdDigits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// dictd reads dictionaries in the format of the dictd server: name.index has a line per word,
+// "word<TAB>offset<TAB>size" with base64 numbers, pointing into name.dict.
+type dictd struct {
+	stem string
+	name string
+
+	once  sync.Once
+	err   error
+	index map[string][]location
+	data  []byte
+}
+
+func newDictd(stem string) *dictd {
+	return &dictd{stem: stem, name: stem[strings.LastIndexAny(stem, `/\`)+1:]}
+}
+
+func (d *dictd) Name() string {
+	return d.name
+}
+
+func (d *dictd) Lookup(word string) ([]string, error) {
+	d.once.Do(d.load)
+	if d.err != nil {
+		return nil, d.err
+	}
+	var definitions []string
+	for _, loc := range d.index[key(word)] {
+		if !loc.within(d.data) {
+			return nil, fmt.Errorf("definition of %q is past the end of %s.dict", word, d.stem)
+		}
+		if text := strings.TrimSpace(string(d.data[loc.offset : loc.offset+loc.size])); text != "" {
+			definitions = append(definitions, text)
+		}
+	}
+	return definitions, nil
+}
+
+// load reads the index and the definitions.
+func (d *dictd) load() {
+	index, err := readData(d.stem + ".index")
+	if err != nil {
+		d.err = fmt.Errorf("failed to read the index: %w", err)
+		return
+	}
+	if d.data, err = readData(d.stem + ".dict"); err != nil {
+		d.err = fmt.Errorf("failed to read the definitions: %w", err)
+		return
+	}
+
+	d.index = make(map[string][]location)
+	for i, line := range strings.Split(string(index), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			d.err = fmt.Errorf("line %d of %s.index has %d fields, want 3", i+1, d.stem, len(fields))
+			return
+		}
+		offset, offsetErr := decodeBase64Number(fields[1])
+		size, sizeErr := decodeBase64Number(fields[2])
+		if offsetErr != nil || sizeErr != nil {
+			d.err = fmt.Errorf("line %d of %s.index has an invalid location", i+1, d.stem)
+			return
+		}
+		// Entries such as 00-database-info describe the dictionary.
+		if strings.HasPrefix(fields[0], "00-database-") || strings.HasPrefix(fields[0], "00database") {
+			continue
+		}
+		d.index[key(fields[0])] = append(d.index[key(fields[0])], location{offset: offset, size: size})
+	}
+}
+
+func decodeBase64Number(s string) (int, error) {
+	n := 0
+	for _, c := range s {
+		digit := strings.IndexRune(dictdDigits, c)
+		if digit < 0 {
+			return 0, fmt.Errorf("invalid digit %q", c)
+		}
+		n = n*64 + digit
+	}
+	return n, nil
+}
@@ -0,0 +1,208 @@
+// Package dictionary looks words up in local StarDict and dictd dictionaries.
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"textreader/internal/file"
+)
+
+// Config tells where the dictionaries are and which ones come first, e.g.
+// {"dir": "/home/leo/dicts", "priority": ["rae", "wordnet"]}.
+type Config struct {
+	Dir string `json:"dir,omitempty"`
+	// Priority lists dictionaries by name or file name, the ones missing go last.
+	Priority []string `json:"priority,omitempty"`
+}
+
+// Dictionary is a dictionary file.
+type Dictionary interface {
+	Name() string
+	// Lookup returns the definitions of word, none when the dictionary doesn't have it.
+	Lookup(word string) ([]string, error)
+}
+
+// Definition is what a dictionary says about a word.
+type Definition struct {
+	Dictionary string
+	Text       string
+}
+
+// Library is the dictionaries of a directory in priority order.
+type Library struct {
+	dictionaries []Dictionary
+	// broken are the errors of the dictionaries that could not be opened.
+	broken []error
+}
+
+// source is a dictionary along with the name of its file, without extension, which the
+// priority can use too.
+type source struct {
+	dictionary Dictionary
+	stem       string
+}
+
+// Open finds the dictionaries in cfg.Dir and its subdirectories: StarDict dictionaries by their
+// .ifo file and dictd dictionaries by their .index file. A missing directory is an empty
+// library. Dictionaries and subdirectories that can't be read are skipped, Broken tells why.
+// The dictionaries are read on their first lookup.
+func Open(cfg Config) (*Library, error) {
+	var (
+		sources []source
+		broken  []error
+	)
+	err := filepath.WalkDir(cfg.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == cfg.Dir {
+				return err
+			}
+			broken = append(broken, err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch {
+		case strings.HasSuffix(path, ".ifo"):
+			stem := strings.TrimSuffix(path, ".ifo")
+			dictionary, err := openStarDict(stem)
+			if err != nil {
+				broken = append(broken, err)
+				return nil
+			}
+			sources = append(sources, source{dictionary: dictionary, stem: filepath.Base(stem)})
+		case strings.HasSuffix(path, ".index"):
+			stem := strings.TrimSuffix(path, ".index")
+			if err := checkData(stem + ".dict"); err != nil {
+				broken = append(broken, fmt.Errorf("dictd dictionary %s: %w", path, err))
+				return nil
+			}
+			sources = append(sources, source{dictionary: newDictd(stem), stem: filepath.Base(stem)})
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) && len(sources) == 0 {
+		return &Library{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionaries: %w", err)
+	}
+
+	rank := func(s source) int {
+		for i, name := range cfg.Priority {
+			if strings.EqualFold(name, s.dictionary.Name()) || strings.EqualFold(name, s.stem) {
+				return i
+			}
+		}
+		return len(cfg.Priority)
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		if ri, rj := rank(sources[i]), rank(sources[j]); ri != rj {
+			return ri < rj
+		}
+		return sources[i].dictionary.Name() < sources[j].dictionary.Name()
+	})
+
+	library := &Library{broken: broken}
+	for _, s := range sources {
+		library.dictionaries = append(library.dictionaries, s.dictionary)
+	}
+	return library, nil
+}
+
+// Len returns how many dictionaries there are.
+func (l *Library) Len() int {
+	return len(l.dictionaries)
+}
+
+// Broken returns why the dictionaries that were skipped could not be opened.
+func (l *Library) Broken() []error {
+	return l.broken
+}
+
+// Lookup returns the definitions of word in every dictionary, in priority order. Words are
+// looked up ignoring case. A dictionary that fails, e.g. because its files are truncated, is
+// left out from then on and added to Broken.
+func (l *Library) Lookup(word string) ([]Definition, error) {
+	var (
+		definitions []Definition
+		healthy     []Dictionary
+	)
+	for _, dictionary := range l.dictionaries {
+		texts, err := dictionary.Lookup(word)
+		if err != nil {
+			l.broken = append(l.broken, fmt.Errorf("failed to read %s: %w", dictionary.Name(), err))
+			continue
+		}
+		healthy = append(healthy, dictionary)
+		for _, text := range texts {
+			definitions = append(definitions, Definition{Dictionary: dictionary.Name(), Text: text})
+		}
+	}
+	l.dictionaries = healthy
+	return definitions, nil
+}
+
+// key is how words are indexed.
+func key(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+// readData reads a dictionary file, path itself or compressed as path.dz (dictzip, which gzip
+// can read) or path.gz.
+func readData(path string) ([]byte, error) {
+	f, err := openData(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, _, err := file.Decompress(f, f.Name())
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// checkData tells whether the dictionary file path, see readData, is there and can be opened.
+func checkData(path string) error {
+	f, err := openData(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func openData(path string) (*os.File, error) {
+	for _, name := range []string{path, path + ".dz", path + ".gz"} {
+		f, err := os.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, fmt.Errorf("%s not found", filepath.Base(path))
+}
+
+var (
+	tagRe       = regexp.MustCompile(`<[^>]*>`)
+	lineBreakRe = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	blankRe     = regexp.MustCompile(`\n{3,}`)
+)
+
+// stripMarkup turns the HTML-like markup of definitions into plain text.
+func stripMarkup(text string) string {
+	text = lineBreakRe.ReplaceAllString(text, "\n")
+	text = tagRe.ReplaceAllString(text, "")
+	text = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ", "&amp;", "&").Replace(text)
+	return strings.TrimSpace(blankRe.ReplaceAllString(text, "\n\n"))
+}
@@ -0,0 +1,159 @@
+package dictionary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeStarDict writes a StarDict dictionary with the definitions of words, compressed when
+// dz is set.
+func writeStarDict(t *testing.T, stem, ifo string, words []string, definitions [][]byte, dz bool) {
+	t.Helper()
+	var idx, dict bytes.Buffer
+	for i, word := range words {
+		idx.WriteString(word)
+		idx.WriteByte(0)
+		_ = binary.Write(&idx, binary.BigEndian, uint32(dict.Len()))
+		_ = binary.Write(&idx, binary.BigEndian, uint32(len(definitions[i])))
+		dict.Write(definitions[i])
+	}
+	dictName, content := stem+".dict", dict.Bytes()
+	if dz {
+		var gz bytes.Buffer
+		w := gzip.NewWriter(&gz)
+		_, _ = w.Write(content)
+		_ = w.Close()
+		dictName, content = stem+".dict.dz", gz.Bytes()
+	}
+	files := map[string][]byte{stem + ".ifo": []byte(ifo), stem + ".idx": idx.Bytes(), dictName: content}
+	for name, data := range files {
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "rae"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeStarDict(t, filepath.Join(dir, "rae", "rae"),
+		"StarDict's dict ifo file\nversion=2.4.2\nbookname=RAE\nsametypesequence=m\n",
+		[]string{"hidalgo", "Rocín"},
+		[][]byte{[]byte("Persona que por su sangre es de una clase noble."), []byte("Caballo de mala traza.")}, true)
+
+	// Without sametypesequence every field starts with its type.
+	html := append([]byte("h"), []byte("<b>rocín</b><br>Caballo de trabajo.\x00")...)
+	writeStarDict(t, filepath.Join(dir, "wiki"),
+		"StarDict's dict ifo file\nversion=2.4.2\nbookname=Wikcionario\n",
+		[]string{"rocín"}, [][]byte{html}, false)
+
+	// dictd: "rocín" at offset 0 with 26 bytes, "A" and "a" in base64.
+	dictdText := "rocín\n  Rocinante, flaco\n"
+	index := "00-database-short\tA\tB\nrocín\tA\ta\n"
+	if err := os.WriteFile(filepath.Join(dir, "sinonimos.index"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sinonimos.dict"), []byte(dictdText), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := Open(Config{Dir: dir, Priority: []string{"wikcionario", "rae"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if library.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", library.Len())
+	}
+
+	got, err := library.Lookup("ROCÍN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Definition{
+		{Dictionary: "Wikcionario", Text: "rocín\nCaballo de trabajo."},
+		{Dictionary: "RAE", Text: "Caballo de mala traza."},
+		{Dictionary: "sinonimos", Text: "rocín\n  Rocinante, flaco"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup(rocín) = %q, want %q", got, want)
+	}
+
+	if got, err := library.Lookup("adarga"); err != nil || len(got) != 0 {
+		t.Errorf("Lookup(adarga) = %q, %v, want no definitions", got, err)
+	}
+}
+
+func TestOpenMissingDirectory(t *testing.T) {
+	library, err := Open(Config{Dir: filepath.Join(t.TempDir(), "missing")})
+	if err != nil || library.Len() != 0 {
+		t.Errorf("Open(missing) = %v, %v, want an empty library", library, err)
+	}
+}
+
+func TestOpenSkipsBrokenDictionaries(t *testing.T) {
+	dir := t.TempDir()
+	writeStarDict(t, filepath.Join(dir, "rae"),
+		"StarDict's dict ifo file\nversion=2.4.2\nbookname=RAE\nsametypesequence=m\n",
+		[]string{"hidalgo"}, [][]byte{[]byte("Persona de clase noble.")}, false)
+	if err := os.WriteFile(filepath.Join(dir, "roto.ifo"), []byte("not a dictionary\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// No .dict: found when opening.
+	writeStarDict(t, filepath.Join(dir, "sin"),
+		"StarDict's dict ifo file\nversion=2.4.2\nbookname=Sin datos\nsametypesequence=m\n",
+		[]string{"hidalgo"}, [][]byte{[]byte("Noble.")}, false)
+	if err := os.Remove(filepath.Join(dir, "sin.dict")); err != nil {
+		t.Fatal(err)
+	}
+	// A truncated .idx: found on the first lookup.
+	writeStarDict(t, filepath.Join(dir, "corto"),
+		"StarDict's dict ifo file\nversion=2.4.2\nbookname=Corto\nsametypesequence=m\n",
+		[]string{"hidalgo"}, [][]byte{[]byte("Noble.")}, false)
+	if err := os.WriteFile(filepath.Join(dir, "corto.idx"), []byte("hidalgo\x00\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := Open(Config{Dir: dir, Priority: []string{"corto"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if library.Len() != 2 || len(library.Broken()) != 2 {
+		t.Fatalf("Len(), Broken() = %d, %v, want 2 dictionaries and 2 broken", library.Len(), library.Broken())
+	}
+	if got, err := library.Lookup("hidalgo"); err != nil || len(got) != 1 || got[0].Dictionary != "RAE" {
+		t.Errorf("Lookup(hidalgo) = %q, %v, want the definition of RAE", got, err)
+	}
+	if library.Len() != 1 || len(library.Broken()) != 3 {
+		t.Errorf("Len(), Broken() = %d, %v, want 1 dictionary and 3 broken", library.Len(), library.Broken())
+	}
+}
+
+func TestStarDictCorruptOffsets(t *testing.T) {
+	stem := filepath.Join(t.TempDir(), "roto")
+	files := map[string][]byte{
+		stem + ".ifo":  []byte("StarDict's dict ifo file\nversion=3.0.0\nbookname=Roto\nidxoffsetbits=64\nsametypesequence=m\n"),
+		stem + ".idx":  append([]byte("hidalgo\x00"), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf0, 0, 0, 0, 4),
+		stem + ".dict": []byte("Noble."),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := openStarDict(stem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.Lookup("hidalgo"); err == nil {
+		t.Errorf("Lookup(hidalgo) = %q, want an error for an offset before the start of the .dict", got)
+	}
+}
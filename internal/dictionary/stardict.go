@@ -0,0 +1,176 @@
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// starDict reads StarDict dictionaries: name.ifo describes it, name.idx lists the words with
+// where their definitions are in name.dict.
+type starDict struct {
+	stem string
+	name string
+	// sameTypeSequence are the types of the fields of every definition, when they are all alike.
+	sameTypeSequence string
+	offsetBits       int
+
+	once  sync.Once
+	err   error
+	index map[string][]location
+	data  []byte
+}
+
+type location struct {
+	offset, size int
+}
+
+// within tells whether the definition at loc is inside data, which a corrupt index may not say.
+func (loc location) within(data []byte) bool {
+	return loc.offset >= 0 && loc.size >= 0 && loc.offset <= len(data)-loc.size
+}
+
+func openStarDict(stem string) (*starDict, error) {
+	f, err := os.Open(stem + ".ifo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open StarDict dictionary: %w", err)
+	}
+	defer f.Close()
+
+	d := &starDict{stem: stem, offsetBits: 32}
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "StarDict's dict ifo file") {
+		return nil, fmt.Errorf("%s.ifo is not a StarDict dictionary", stem)
+	}
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "bookname":
+			d.name = strings.TrimSpace(value)
+		case "sametypesequence":
+			d.sameTypeSequence = strings.TrimSpace(value)
+		case "idxoffsetbits":
+			if d.offsetBits, err = strconv.Atoi(strings.TrimSpace(value)); err != nil || (d.offsetBits != 32 && d.offsetBits != 64) {
+				return nil, fmt.Errorf("%s.ifo has an invalid idxoffsetbits %q", stem, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s.ifo: %w", stem, err)
+	}
+	if d.name == "" {
+		d.name = stem[strings.LastIndexAny(stem, `/\`)+1:]
+	}
+	for _, extension := range []string{".idx", ".dict"} {
+		if err := checkData(stem + extension); err != nil {
+			return nil, fmt.Errorf("StarDict dictionary %s.ifo: %w", stem, err)
+		}
+	}
+	return d, nil
+}
+
+func (d *starDict) Name() string {
+	return d.name
+}
+
+func (d *starDict) Lookup(word string) ([]string, error) {
+	d.once.Do(d.load)
+	if d.err != nil {
+		return nil, d.err
+	}
+	var definitions []string
+	for _, loc := range d.index[key(word)] {
+		if !loc.within(d.data) {
+			return nil, fmt.Errorf("definition of %q is past the end of %s.dict", word, d.stem)
+		}
+		if text := d.parse(d.data[loc.offset : loc.offset+loc.size]); text != "" {
+			definitions = append(definitions, text)
+		}
+	}
+	return definitions, nil
+}
+
+// load reads the index and the definitions.
+func (d *starDict) load() {
+	idx, err := readData(d.stem + ".idx")
+	if err != nil {
+		d.err = fmt.Errorf("failed to read the index: %w", err)
+		return
+	}
+	if d.data, err = readData(d.stem + ".dict"); err != nil {
+		d.err = fmt.Errorf("failed to read the definitions: %w", err)
+		return
+	}
+
+	d.index = make(map[string][]location)
+	offsetSize := d.offsetBits / 8
+	for len(idx) > 0 {
+		end := bytes.IndexByte(idx, 0)
+		if end < 0 || len(idx) < end+1+offsetSize+4 {
+			d.err = fmt.Errorf("%s.idx is truncated", d.stem)
+			return
+		}
+		word := string(idx[:end])
+		idx = idx[end+1:]
+		var loc location
+		if offsetSize == 8 {
+			loc.offset = int(binary.BigEndian.Uint64(idx))
+		} else {
+			loc.offset = int(binary.BigEndian.Uint32(idx))
+		}
+		loc.size = int(binary.BigEndian.Uint32(idx[offsetSize:]))
+		idx = idx[offsetSize+4:]
+		d.index[key(word)] = append(d.index[key(word)], loc)
+	}
+}
+
+// parse returns the text of a definition. Each field has a type: lowercase types are text
+// ending with a NUL, uppercase ones binary data after their size. With a sametypesequence the
+// types are not repeated and the last field takes the rest of the definition.
+func (d *starDict) parse(data []byte) string {
+	var texts []string
+	types := d.sameTypeSequence
+	for i := 0; len(data) > 0 && (types == "" || i < len(types)); i++ {
+		var t byte
+		if types == "" {
+			t, data = data[0], data[1:]
+		} else {
+			t = types[i]
+		}
+		last := types != "" && i == len(types)-1
+
+		var field []byte
+		switch {
+		case last:
+			field, data = data, nil
+		case t >= 'a' && t <= 'z':
+			end := bytes.IndexByte(data, 0)
+			if end < 0 {
+				end = len(data)
+			}
+			field, data = data[:end], data[min(end+1, len(data)):]
+		default:
+			if len(data) < 4 {
+				return strings.Join(texts, "\n")
+			}
+			size := min(int(binary.BigEndian.Uint32(data)), len(data)-4)
+			field, data = data[4:4+size], data[4+size:]
+		}
+
+		switch t {
+		case 'm', 'l', 't', 'y', 'k':
+			texts = append(texts, strings.TrimSpace(string(field)))
+		case 'g', 'h', 'x':
+			texts = append(texts, stripMarkup(string(field)))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
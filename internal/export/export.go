@@ -0,0 +1,257 @@
+// Package export writes the vocabulary as CSV, TSV or a text file Anki can import.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"textreader/internal/file"
+	"textreader/internal/model"
+)
+
+// Formats the vocabulary can be exported to.
+const (
+	FormatCSV  = "csv"
+	FormatTSV  = "tsv"
+	FormatAnki = "anki"
+)
+
+// Fields a word can be exported with.
+const (
+	FieldWord    = "word"
+	FieldContext = "context"
+	FieldNote    = "note"
+	FieldBook    = "book"
+	FieldLine    = "line"
+	FieldAdded   = "added"
+)
+
+// BookTag is replaced by the title of the book of the word in Config.Tags.
+const BookTag = "{book}"
+
+var (
+	allFields = []string{FieldWord, FieldContext, FieldBook, FieldLine, FieldNote, FieldAdded}
+	// defaultAnkiFields make the front of a note the word and the back where it was found.
+	defaultAnkiFields = []string{FieldWord, FieldContext, FieldBook}
+)
+
+// Config tells how the vocabulary is exported, e.g.
+// {"format": "anki", "fields": ["word", "context", "note"], "tags": ["spanish", "{book}"], "deck": "Vocabulary"}.
+type Config struct {
+	// Format is the one of the in-app export, anki by default.
+	Format string `json:"format,omitempty"`
+	// Fields are the fields of the Anki notes in order, word, context and book by default.
+	Fields []string `json:"fields,omitempty"`
+	// Tags are added to every Anki note, {book} being the title of the book of the word.
+	Tags []string `json:"tags,omitempty"`
+	// Deck and Notetype are where Anki imports the notes, the ones chosen in Anki by default.
+	Deck     string `json:"deck,omitempty"`
+	Notetype string `json:"notetype,omitempty"`
+}
+
+// Word is a word of the vocabulary along with the book it was saved in.
+type Word struct {
+	model.VocabEntry
+	Book string
+}
+
+// BookWords returns the vocabulary of a book, named by its title or else by its file name.
+func BookWords(title, fileName string, vocabulary []model.VocabEntry) []Word {
+	if title == "" {
+		title = filepath.Base(fileName)
+	}
+	words := make([]Word, 0, len(vocabulary))
+	for _, entry := range vocabulary {
+		words = append(words, Word{VocabEntry: entry, Book: title})
+	}
+	return words
+}
+
+// Vocabulary returns the vocabulary of the books in the progress entries matching book, by
+// title or file name, every one when it is empty, sorted by book.
+func Vocabulary(entries map[string]file.ProgressEntry, book string) []Word {
+	var books [][]Word
+	for _, entry := range entries {
+		words := BookWords(entry.Title, entry.FileName, entry.Vocabulary)
+		if len(words) == 0 {
+			continue
+		}
+		if book != "" && !containsFold(entry.Title, book) && !containsFold(entry.FileName, book) {
+			continue
+		}
+		books = append(books, words)
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i][0].Book < books[j][0].Book })
+
+	var words []Word
+	for _, b := range books {
+		words = append(words, b...)
+	}
+	return words
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// Validate checks that cfg has a known format and fields.
+func Validate(cfg Config) error {
+	if cfg.Format != "" {
+		if _, err := Extension(cfg.Format); err != nil {
+			return err
+		}
+	}
+	for _, field := range cfg.Fields {
+		if !slices.Contains(allFields, field) {
+			return fmt.Errorf("unknown field %q, use %s", field, strings.Join(allFields, ", "))
+		}
+	}
+	return nil
+}
+
+// Extension returns the extension of the files written in format.
+func Extension(format string) (string, error) {
+	switch format {
+	case FormatCSV:
+		return ".csv", nil
+	case FormatTSV:
+		return ".tsv", nil
+	case FormatAnki:
+		return ".txt", nil
+	default:
+		return "", fmt.Errorf("unknown format %q, use %s, %s or %s", format, FormatCSV, FormatTSV, FormatAnki)
+	}
+}
+
+// Write writes words in format. CSV and TSV have a header and every field, Anki notes have the
+// fields and tags of cfg.
+func Write(w io.Writer, format string, words []Word, cfg Config) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, words)
+	case FormatTSV:
+		return writeTSV(w, words)
+	case FormatAnki:
+		return writeAnki(w, words, cfg)
+	default:
+		_, err := Extension(format)
+		return err
+	}
+}
+
+func writeCSV(w io.Writer, words []Word) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(allFields); err != nil {
+		return fmt.Errorf("failed to write the vocabulary: %w", err)
+	}
+	for _, word := range words {
+		if err := writer.Write(fields(word, allFields)); err != nil {
+			return fmt.Errorf("failed to write the vocabulary: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write the vocabulary: %w", err)
+	}
+	return nil
+}
+
+func writeTSV(w io.Writer, words []Word) error {
+	var b strings.Builder
+	b.WriteString(strings.Join(allFields, "\t") + "\n")
+	for _, word := range words {
+		b.WriteString(strings.Join(tabSafe(fields(word, allFields)), "\t") + "\n")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write the vocabulary: %w", err)
+	}
+	return nil
+}
+
+// writeAnki writes the notes as a text file with the headers Anki (2.1.55 and later) reads the
+// separator, deck, note type and tags column from.
+func writeAnki(w io.Writer, words []Word, cfg Config) error {
+	noteFields := cfg.Fields
+	if len(noteFields) == 0 {
+		noteFields = defaultAnkiFields
+	}
+
+	var b strings.Builder
+	b.WriteString("#separator:tab\n#html:false\n")
+	if cfg.Notetype != "" {
+		fmt.Fprintf(&b, "#notetype:%s\n", cfg.Notetype)
+	}
+	if cfg.Deck != "" {
+		fmt.Fprintf(&b, "#deck:%s\n", cfg.Deck)
+	}
+	if len(cfg.Tags) > 0 {
+		fmt.Fprintf(&b, "#tags column:%d\n", len(noteFields)+1)
+	}
+	for _, word := range words {
+		row := tabSafe(fields(word, noteFields))
+		if len(cfg.Tags) > 0 {
+			row = append(row, tags(word, cfg.Tags))
+		}
+		b.WriteString(strings.Join(row, "\t") + "\n")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write the vocabulary: %w", err)
+	}
+	return nil
+}
+
+// fields returns the values of names for word, empty for an unknown line or date.
+func fields(word Word, names []string) []string {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch name {
+		case FieldWord:
+			value = word.Word
+		case FieldContext:
+			value = word.Sentence
+		case FieldNote:
+			value = word.Note
+		case FieldBook:
+			value = word.Book
+		case FieldLine:
+			if word.Line >= 0 {
+				value = strconv.Itoa(word.Line)
+			}
+		case FieldAdded:
+			if !word.Added.IsZero() {
+				value = word.Added.Local().Format("2006-01-02")
+			}
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// tabSafe keeps values on their column by turning tabs and line breaks into spaces.
+func tabSafe(values []string) []string {
+	replacer := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	for i, value := range values {
+		values[i] = replacer.Replace(value)
+	}
+	return values
+}
+
+// tags writes the tags of word, spaces separating tags in Anki.
+func tags(word Word, names []string) string {
+	var result []string
+	for _, tag := range names {
+		if tag == BookTag {
+			tag = word.Book
+		}
+		if tag = strings.Join(strings.Fields(tag), "_"); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return strings.Join(result, " ")
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"textreader/internal/file"
+	"textreader/internal/model"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	added := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)
+	words := append(
+		BookWords("Don Quijote", "/tmp/quijote.txt", []model.VocabEntry{
+			{Word: "adarga", Sentence: "lanza en astillero, adarga antigua", Line: 12, Added: added, Note: "escudo\tde cuero"},
+			{Word: "hidalgo", Line: -1},
+		}),
+		BookWords("", "/tmp/lazarillo.txt", []model.VocabEntry{{Word: "ciego", Sentence: "un \"ciego\"\nque", Line: 3}})...,
+	)
+
+	tests := []struct {
+		name   string
+		format string
+		cfg    Config
+		want   string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			want: "word,context,book,line,note,added\n" +
+				"adarga,\"lanza en astillero, adarga antigua\",Don Quijote,12,escudo\tde cuero,2024-03-01\n" +
+				"hidalgo,,Don Quijote,,,\n" +
+				"ciego,\"un \"\"ciego\"\"\nque\",lazarillo.txt,3,,\n",
+		},
+		{
+			name:   "tsv",
+			format: FormatTSV,
+			want: "word\tcontext\tbook\tline\tnote\tadded\n" +
+				"adarga\tlanza en astillero, adarga antigua\tDon Quijote\t12\tescudo de cuero\t2024-03-01\n" +
+				"hidalgo\t\tDon Quijote\t\t\t\n" +
+				"ciego\tun \"ciego\" que\tlazarillo.txt\t3\t\t\n",
+		},
+		{
+			name:   "anki defaults",
+			format: FormatAnki,
+			want: "#separator:tab\n#html:false\n" +
+				"adarga\tlanza en astillero, adarga antigua\tDon Quijote\n" +
+				"hidalgo\t\tDon Quijote\n" +
+				"ciego\tun \"ciego\" que\tlazarillo.txt\n",
+		},
+		{
+			name:   "anki mapping and tags",
+			format: FormatAnki,
+			cfg:    Config{Fields: []string{"word", "note"}, Tags: []string{"español", BookTag}, Deck: "Vocabulario", Notetype: "Basic"},
+			want: "#separator:tab\n#html:false\n#notetype:Basic\n#deck:Vocabulario\n#tags column:3\n" +
+				"adarga\tescudo de cuero\tespañol Don_Quijote\n" +
+				"hidalgo\t\tespañol Don_Quijote\n" +
+				"ciego\t\tespañol lazarillo.txt\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, tt.format, words, tt.cfg); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+
+	if err := Write(&strings.Builder{}, "xlsx", words, Config{}); err == nil {
+		t.Error("Write() expected an error for an unknown format")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		cfg     Config
+		wantErr bool
+	}{
+		{cfg: Config{}},
+		{cfg: Config{Format: FormatTSV, Fields: []string{"word", "context", "added"}}},
+		{cfg: Config{Format: "pdf"}, wantErr: true},
+		{cfg: Config{Fields: []string{"word", "definition"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.cfg); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) = %v, want error %v", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestVocabulary(t *testing.T) {
+	entries := map[string]file.ProgressEntry{
+		"a": {FileName: "/tmp/quijote.txt", Title: "Don Quijote", Vocabulary: []model.VocabEntry{{Word: "adarga"}, {Word: "hidalgo"}}},
+		"b": {FileName: "/tmp/lazarillo.txt", Vocabulary: []model.VocabEntry{{Word: "ciego"}}},
+		"c": {FileName: "/tmp/celestina.txt", Title: "La Celestina"},
+	}
+
+	tests := []struct {
+		book string
+		want string
+	}{
+		{book: "", want: "adarga hidalgo ciego"},
+		{book: "quijote", want: "adarga hidalgo"},
+		{book: "LAZARILLO", want: "ciego"},
+		{book: "celestina", want: ""},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, word := range Vocabulary(entries, tt.book) {
+			got = append(got, word.Word)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("Vocabulary(%q) got=[%s], want=[%s]", tt.book, strings.Join(got, " "), tt.want)
+		}
+	}
+}
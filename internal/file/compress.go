@@ -0,0 +1,73 @@
+package file
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zipMagic   = []byte("PK\x03\x04")
+)
+
+// Decompress detects gzip, bzip2 and single-entry zip content by its magic bytes and returns
+// a reader over the decompressed stream together with the name of the decompressed content
+// ("book.txt.gz" becomes "book.txt"). Anything else, including zip containers with several
+// entries such as EPUB, is returned unchanged.
+func Decompress(r io.Reader, name string) (io.Reader, string, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zipMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		return gr, trimExt(name, ".gz", ".gzip"), nil
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(br), trimExt(name, ".bz2", ".bzip2"), nil
+	case bytes.HasPrefix(magic, zipMagic):
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read zip file: %w", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open zip file: %w", err)
+		}
+		entries := make([]*zip.File, 0, 1)
+		for _, f := range zr.File {
+			if !f.FileInfo().IsDir() {
+				entries = append(entries, f)
+			}
+		}
+		if len(entries) != 1 {
+			return bytes.NewReader(data), name, nil
+		}
+		rc, err := entries[0].Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open %s in zip file: %w", entries[0].Name, err)
+		}
+		return rc, path.Base(entries[0].Name), nil
+	default:
+		return br, name, nil
+	}
+}
+
+func trimExt(name string, exts ...string) string {
+	for _, ext := range exts {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
@@ -0,0 +1,71 @@
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+)
+
+func TestDecompress(t *testing.T) {
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, _ = gw.Write([]byte("hola\nmundo\n"))
+	_ = gw.Close()
+
+	bz2 := []byte{
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x52, 0x39,
+		0x85, 0xc1, 0x00, 0x00, 0x02, 0x41, 0x80, 0x00, 0x10, 0x24, 0x47, 0x82,
+		0x00, 0x20, 0x00, 0x22, 0x03, 0x47, 0xa4, 0x20, 0xc9, 0x88, 0x2f, 0x44,
+		0xa9, 0xf0, 0xfc, 0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x41, 0x48, 0xe6, 0x17,
+		0x04,
+	}
+
+	singleEntry := buildZip(t, map[string]string{"dir/libro.txt": "hola\nmundo\n"})
+	multiEntry := buildZip(t, map[string]string{"a.txt": "a", "b.txt": "b"})
+
+	type test struct {
+		name     string
+		data     []byte
+		wantName string
+		want     []string
+	}
+
+	tests := []test{
+		{name: "libro.txt.gz", data: gz.Bytes(), wantName: "libro.txt", want: []string{"hola", "mundo"}},
+		{name: "libro.txt.bz2", data: bz2, wantName: "libro.txt", want: []string{"hola", "mundo"}},
+		{name: "libro.zip", data: readAll(t, singleEntry), wantName: "libro.txt", want: []string{"hola", "mundo"}},
+		{name: "libro.txt", data: []byte("hola\nmundo"), wantName: "libro.txt", want: []string{"hola", "mundo"}},
+	}
+
+	for _, tc := range tests {
+		r, name, err := Decompress(bytes.NewReader(tc.data), tc.name)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if name != tc.wantName {
+			t.Errorf("got=[%s], want=[%s]", name, tc.wantName)
+		}
+		if got, _ := ReadLines(r); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("got=[%s], want=[%s]", got, tc.want)
+		}
+	}
+
+	raw := readAll(t, multiEntry)
+	r, name, err := Decompress(bytes.NewReader(raw), "libro.epub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readAll(t, r); name != "libro.epub" || !bytes.Equal(got, raw) {
+		t.Errorf("multi-entry zip should be returned unchanged, got name=[%s]", name)
+	}
+}
+
+func readAll(t *testing.T, r interface{ Read([]byte) (int, error) }) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,146 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+	"textreader/internal/wrap"
+)
+
+// Document is a loaded book: its text lines plus the structure the source format provides.
+type Document struct {
+	Lines    []string
+	Chapters []model.Chapter
+	// Styles maps a line index to the label style used to draw it, e.g. headings.
+	Styles   map[int]string
+	Metadata model.BookMetadata
+	// PageBreaks holds the line where each page starts, for paginated formats like PDF.
+	PageBreaks []int
+}
+
+// LoadOptions tweaks how a document is read.
+type LoadOptions struct {
+	// Encoding of plain text files, "auto" to detect it.
+	Encoding string
+	// Format forces a format by name instead of guessing it from the file.
+	Format string
+	// Registry holds the formats to choose from, the built-in ones when nil.
+	Registry *Registry
+	// conversions is how many converters the book went through before this load.
+	conversions int
+}
+
+func (opts LoadOptions) registry() *Registry {
+	if opts.Registry == nil {
+		return NewRegistry()
+	}
+	return opts.Registry
+}
+
+// LoadDocument reads the book at filePath, see ReadDocument.
+func LoadDocument(filePath string, opts LoadOptions) (Document, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	return ReadDocument(f, filepath.Base(filePath), opts)
+}
+
+// ReadDocument reads a book from r, decompressing it if needed, and loads it with the format
+// the registry of opts chooses for the (decompressed) name and content. Text is always returned as UTF-8.
+func ReadDocument(r io.Reader, name string, opts LoadOptions) (Document, error) {
+	r, name, err := Decompress(r, name)
+	if err != nil {
+		return Document{}, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	format, err := opts.registry().Find(name, opts.Format, data)
+	if err != nil {
+		return Document{}, err
+	}
+	return format.Loader.Load(data, opts)
+}
+
+func renderedDocument(rendered markup.Rendered) Document {
+	doc := Document{Lines: []string{}, Chapters: []model.Chapter{}, Styles: map[int]string{}}
+	doc.appendRendered(rendered, true)
+	return doc
+}
+
+// appendRendered adds rendered lines at the end of the document, moving their styles (and
+// chapters when withChapters is set) along with them.
+func (d *Document) appendRendered(rendered markup.Rendered, withChapters bool) {
+	offset := len(d.Lines)
+	d.Lines = append(d.Lines, rendered.Lines...)
+	for line, style := range rendered.Styles {
+		d.Styles[offset+line] = style
+	}
+	if withChapters {
+		for _, chapter := range rendered.Chapters {
+			d.Chapters = append(d.Chapters, model.Chapter{Title: chapter.Title, Line: offset + chapter.Line})
+		}
+	}
+}
+
+// paragraph adds s, wrapped to model.WrapWidth, after a blank line. Its first line is
+// prefixed with first and the following ones with rest.
+func (d *Document) paragraph(s, first, rest string) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	d.blank()
+	d.lines(s, first, rest)
+}
+
+// lines is like paragraph but continues right after the last line.
+func (d *Document) lines(s, first, rest string) {
+	prefix := first
+	for _, line := range wrap.Paragraph(s, model.WrapWidth-len([]rune(rest))) {
+		d.Lines = append(d.Lines, prefix+line)
+		prefix = rest
+	}
+}
+
+func (d *Document) styled(s, style string) {
+	wrapped := wrap.Paragraph(s, model.WrapWidth)
+	if len(wrapped) == 0 {
+		return
+	}
+	d.blank()
+	for _, line := range wrapped {
+		d.Styles[len(d.Lines)] = style
+		d.Lines = append(d.Lines, line)
+	}
+}
+
+func (d *Document) blank() {
+	if n := len(d.Lines); n > 0 && d.Lines[n-1] != "" {
+		d.Lines = append(d.Lines, "")
+	}
+}
+
+// notes adds a "Notes" chapter at the end of the document, note i being referenced in the
+// text as [i+1].
+func (d *Document) notes(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+	d.blank()
+	d.Chapters = append(d.Chapters, model.Chapter{Title: "Notes", Line: len(d.Lines)})
+	d.styled("Notes", markup.HeadingStyle)
+	for i, note := range notes {
+		marker := fmt.Sprintf("[%d] ", i+1)
+		d.paragraph(marker+note, "", strings.Repeat(" ", len(marker)))
+	}
+}
@@ -0,0 +1,155 @@
+package file
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	AutoEncoding    = "auto"
+	UTF8Encoding    = "utf-8"
+	UTF16LEEncoding = "utf-16le"
+	UTF16BEEncoding = "utf-16be"
+	Latin1Encoding  = "iso-8859-1"
+	CP1252Encoding  = "windows-1252"
+	CP1251Encoding  = "windows-1251"
+)
+
+var (
+	utf8BOM    = []byte{0xef, 0xbb, 0xbf}
+	utf16LEBOM = []byte{0xff, 0xfe}
+	utf16BEBOM = []byte{0xfe, 0xff}
+)
+
+// cp1252 maps the 0x80-0x9F range of Windows-1252, where it differs from ISO-8859-1.
+// Zero entries are undefined in Windows-1252.
+var cp1252 = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+// cp1251 maps the upper half (0x80-0xFF) of the Windows-1251 Cyrillic code page.
+var cp1251 = [128]rune{
+	'Ђ', 'Ѓ', '‚', 'ѓ', '„', '…', '†', '‡', '€', '‰', 'Љ', '‹', 'Њ', 'Ќ', 'Ћ', 'Џ',
+	'ђ', '‘', '’', '“', '”', '•', '–', '—', 0, '™', 'љ', '›', 'њ', 'ќ', 'ћ', 'џ',
+	'\u00a0', 'Ў', 'ў', 'Ј', '¤', 'Ґ', '¦', '§', 'Ё', '©', 'Є', '«', '¬', '\u00ad', '®', 'Ї',
+	'°', '±', 'І', 'і', 'ґ', 'µ', '¶', '·', 'ё', '№', 'є', '»', 'ј', 'Ѕ', 'ѕ', 'ї',
+	'А', 'Б', 'В', 'Г', 'Д', 'Е', 'Ж', 'З', 'И', 'Й', 'К', 'Л', 'М', 'Н', 'О', 'П',
+	'Р', 'С', 'Т', 'У', 'Ф', 'Х', 'Ц', 'Ч', 'Ш', 'Щ', 'Ъ', 'Ы', 'Ь', 'Э', 'Ю', 'Я',
+	'а', 'б', 'в', 'г', 'д', 'е', 'ж', 'з', 'и', 'й', 'к', 'л', 'м', 'н', 'о', 'п',
+	'р', 'с', 'т', 'у', 'ф', 'х', 'ц', 'ч', 'ш', 'щ', 'ъ', 'ы', 'ь', 'э', 'ю', 'я',
+}
+
+var encodingAliases = map[string]string{
+	"utf8":         UTF8Encoding,
+	"utf-8":        UTF8Encoding,
+	"utf16le":      UTF16LEEncoding,
+	"utf-16le":     UTF16LEEncoding,
+	"utf16be":      UTF16BEEncoding,
+	"utf-16be":     UTF16BEEncoding,
+	"latin1":       Latin1Encoding,
+	"latin-1":      Latin1Encoding,
+	"iso8859-1":    Latin1Encoding,
+	"iso-8859-1":   Latin1Encoding,
+	"cp1252":       CP1252Encoding,
+	"windows1252":  CP1252Encoding,
+	"windows-1252": CP1252Encoding,
+	"cp1251":       CP1251Encoding,
+	"windows1251":  CP1251Encoding,
+	"windows-1251": CP1251Encoding,
+}
+
+// DetectEncoding guesses the encoding of data: a BOM wins, then valid UTF-8, then
+// Windows-1252 if any of its printable 0x80-0x9F characters appear, and ISO-8859-1 otherwise.
+func DetectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return UTF8Encoding
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return UTF16LEEncoding
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return UTF16BEEncoding
+	case utf8.Valid(data):
+		return UTF8Encoding
+	}
+	for _, b := range data {
+		if b >= 0x80 && b <= 0x9f && cp1252[b-0x80] != 0 {
+			return CP1252Encoding
+		}
+	}
+	return Latin1Encoding
+}
+
+// DecodeToUTF8 converts data from the given encoding ("auto" detects it) to a UTF-8 string.
+func DecodeToUTF8(data []byte, encoding string) (string, error) {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if encoding == "" || encoding == AutoEncoding {
+		encoding = DetectEncoding(data)
+	}
+	name, ok := encodingAliases[encoding]
+	if !ok {
+		return "", fmt.Errorf("unsupported encoding %q", encoding)
+	}
+
+	switch name {
+	case UTF8Encoding:
+		return string(bytes.TrimPrefix(data, utf8BOM)), nil
+	case UTF16LEEncoding:
+		return decodeUTF16(bytes.TrimPrefix(data, utf16LEBOM), binary.LittleEndian), nil
+	case UTF16BEEncoding:
+		return decodeUTF16(bytes.TrimPrefix(data, utf16BEBOM), binary.BigEndian), nil
+	case CP1252Encoding:
+		return decodeSingleByte(data, cp1252Rune), nil
+	case CP1251Encoding:
+		return decodeSingleByte(data, func(b byte) rune {
+			if b >= 0x80 && cp1251[b-0x80] != 0 {
+				return cp1251[b-0x80]
+			}
+			return rune(b)
+		}), nil
+	default:
+		return decodeSingleByte(data, func(b byte) rune { return rune(b) }), nil
+	}
+}
+
+func cp1252Rune(b byte) rune {
+	if b >= 0x80 && b <= 0x9f && cp1252[b-0x80] != 0 {
+		return cp1252[b-0x80]
+	}
+	return rune(b)
+}
+
+// charsetReader lets encoding/xml read documents declared in any encoding DecodeToUTF8 knows.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	content, err := DecodeToUTF8(data, label)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(content), nil
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, order.Uint16(data[i:]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func decodeSingleByte(data []byte, toRune func(byte) rune) string {
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for _, b := range data {
+		sb.WriteRune(toRune(b))
+	}
+	return sb.String()
+}
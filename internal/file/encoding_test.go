@@ -0,0 +1,55 @@
+package file
+
+import "testing"
+
+func TestDetectEncoding(t *testing.T) {
+	type test struct {
+		data []byte
+		want string
+	}
+
+	tests := []test{
+		{data: []byte("año"), want: UTF8Encoding},
+		{data: []byte{0xef, 0xbb, 0xbf, 'a'}, want: UTF8Encoding},
+		{data: []byte{0xff, 0xfe, 'a', 0}, want: UTF16LEEncoding},
+		{data: []byte{'a', 0xf1, 'o'}, want: Latin1Encoding},
+		{data: []byte{0x93, 'a', 0xf1, 'o', 0x94}, want: CP1252Encoding},
+	}
+
+	for _, tc := range tests {
+		if got := DetectEncoding(tc.data); got != tc.want {
+			t.Errorf("got=[%s], want=[%s]", got, tc.want)
+		}
+	}
+}
+
+func TestDecodeToUTF8(t *testing.T) {
+	type test struct {
+		data     []byte
+		encoding string
+		want     string
+	}
+
+	tests := []test{
+		{data: []byte{'a', 0xf1, 'o'}, encoding: AutoEncoding, want: "año"},
+		{data: []byte{0x93, 'a', 0xf1, 'o', 0x94, 0x80}, encoding: AutoEncoding, want: "“año”€"},
+		{data: []byte{0x93, 'a'}, encoding: "latin1", want: "\u0093a"},
+		{data: []byte{0xef, 0xbb, 0xbf, 'a', 0xc3, 0xb1}, encoding: AutoEncoding, want: "añ"},
+		{data: []byte{0xfe, 0xff, 0, 'a', 0, 0xf1}, encoding: AutoEncoding, want: "añ"},
+		{data: []byte{'a', 0, 0xf1, 0}, encoding: "UTF-16LE", want: "añ"},
+	}
+
+	for _, tc := range tests {
+		got, err := DecodeToUTF8(tc.data, tc.encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("got=[%s], want=[%s]", got, tc.want)
+		}
+	}
+
+	if _, err := DecodeToUTF8([]byte("a"), "klingon"); err == nil {
+		t.Errorf("expected an error for an unknown encoding")
+	}
+}
@@ -0,0 +1,213 @@
+package file
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+)
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Manifest []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+	Spine struct {
+		Toc      string `xml:"toc,attr"`
+		ItemRefs []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+type ncxNavPoint struct {
+	Label   string        `xml:"navLabel>text"`
+	Content ncxContent    `xml:"content"`
+	Points  []ncxNavPoint `xml:"navPoint"`
+}
+
+type ncxContent struct {
+	Src string `xml:"src,attr"`
+}
+
+type ncxDocument struct {
+	Points []ncxNavPoint `xml:"navMap>navPoint"`
+}
+
+// ReadEPUB follows the OPF spine of an EPUB container and turns every chapter into wrapped
+// plain lines. Each spine document starts a new chapter.
+func ReadEPUB(r io.ReaderAt, size int64) (Document, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to open epub: %w", err)
+	}
+
+	var container epubContainer
+	if err := decodeZipXML(zr, "META-INF/container.xml", &container); err != nil {
+		return Document{}, err
+	}
+	if len(container.Rootfiles) == 0 {
+		return Document{}, fmt.Errorf("epub container has no rootfile")
+	}
+
+	opfPath := container.Rootfiles[0].FullPath
+	var pkg epubPackage
+	if err := decodeZipXML(zr, opfPath, &pkg); err != nil {
+		return Document{}, err
+	}
+	opfDir := path.Dir(opfPath)
+
+	hrefs := make(map[string]string)
+	titles := make(map[string]string)
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = resolveHref(opfDir, item.Href)
+		switch {
+		case item.ID == pkg.Spine.Toc || item.MediaType == "application/x-dtbncx+xml":
+			loadNCXTitles(zr, hrefs[item.ID], titles)
+		case strings.Contains(item.Properties, "nav"):
+			loadNavTitles(zr, hrefs[item.ID], titles)
+		}
+	}
+
+	doc := Document{Lines: []string{}, Chapters: []model.Chapter{}, Styles: map[int]string{}}
+	for i, ref := range pkg.Spine.ItemRefs {
+		href, ok := hrefs[ref.IDRef]
+		if !ok {
+			continue
+		}
+		f, err := openZipFile(zr, href)
+		if err != nil {
+			return Document{}, err
+		}
+		rendered, err := markup.RenderHTML(f, model.WrapWidth)
+		f.Close()
+		if err != nil {
+			return Document{}, fmt.Errorf("failed to parse %s: %w", href, err)
+		}
+		if len(rendered.Lines) == 0 {
+			continue
+		}
+
+		title := titles[href]
+		if title == "" && len(rendered.Chapters) > 0 {
+			title = rendered.Chapters[0].Title
+		}
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", i+1)
+		}
+		if len(doc.Lines) > 0 {
+			doc.Lines = append(doc.Lines, "")
+		}
+		doc.Chapters = append(doc.Chapters, model.Chapter{Title: title, Line: len(doc.Lines)})
+		doc.appendRendered(rendered, false)
+	}
+	return doc, nil
+}
+
+func resolveHref(dir, href string) string {
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	if i := strings.Index(href, "#"); i >= 0 {
+		href = href[:i]
+	}
+	return path.Join(dir, href)
+}
+
+func openZipFile(zr *zip.Reader, name string) (io.ReadCloser, error) {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f.Open()
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+func decodeZipXML(zr *zip.Reader, name string, v interface{}) error {
+	f, err := openZipFile(zr, name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := markup.NewHTMLDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func loadNCXTitles(zr *zip.Reader, ncxPath string, titles map[string]string) {
+	var ncx ncxDocument
+	if err := decodeZipXML(zr, ncxPath, &ncx); err != nil {
+		return
+	}
+	var walk func(points []ncxNavPoint)
+	walk = func(points []ncxNavPoint) {
+		for _, p := range points {
+			href := resolveHref(path.Dir(ncxPath), p.Content.Src)
+			if _, seen := titles[href]; !seen {
+				titles[href] = strings.TrimSpace(p.Label)
+			}
+			walk(p.Points)
+		}
+	}
+	walk(ncx.Points)
+}
+
+// loadNavTitles reads the links of an EPUB 3 navigation document.
+func loadNavTitles(zr *zip.Reader, navPath string, titles map[string]string) {
+	f, err := openZipFile(zr, navPath)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	decoder := markup.NewHTMLDecoder(f)
+	href := ""
+	var label strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "a" {
+				href = ""
+				label.Reset()
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "href" {
+						href = resolveHref(path.Dir(navPath), attr.Value)
+					}
+				}
+			}
+		case xml.CharData:
+			if href != "" {
+				label.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "a" && href != "" {
+				if _, seen := titles[href]; !seen {
+					titles[href] = collapseSpaces(label.String())
+				}
+				href = ""
+			}
+		}
+	}
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
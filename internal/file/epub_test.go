@@ -0,0 +1,79 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+	"textreader/internal/model"
+)
+
+func buildZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestReadEPUB(t *testing.T) {
+	epub := buildZip(t, map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`,
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0">
+  <manifest>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="c2" href="text/c2.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c1" href="text/c1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine toc="ncx"><itemref idref="c1"/><itemref idref="c2"/></spine>
+</package>`,
+		"OEBPS/toc.ncx": `<?xml version="1.0"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/"><navMap>
+  <navPoint><navLabel><text>Uno</text></navLabel><content src="text/c1.xhtml#start"/></navPoint>
+</navMap></ncx>`,
+		"OEBPS/text/c1.xhtml": `<html><head><title>x</title><style>p {}</style></head>
+<body><h1>Capítulo I</h1><p>En un lugar de la Mancha,
+de cuyo nombre no quiero acordarme&nbsp;...</p></body></html>`,
+		"OEBPS/text/c2.xhtml": `<html><body><h2>Capítulo II</h2><p>Fin.<br/>Adiós</p></body></html>`,
+	})
+
+	doc, err := ReadEPUB(epub, epub.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLines := []string{
+		"Capítulo I",
+		"",
+		"En un lugar de la Mancha, de cuyo nombre no quiero acordarme ...",
+		"",
+		"Capítulo II",
+		"",
+		"Fin.",
+		"Adiós",
+	}
+	if !reflect.DeepEqual(doc.Lines, wantLines) {
+		t.Errorf("got=[%q], want=[%q]", doc.Lines, wantLines)
+	}
+
+	wantChapters := []model.Chapter{{Title: "Uno", Line: 0}, {Title: "Capítulo II", Line: 4}}
+	if !reflect.DeepEqual(doc.Chapters, wantChapters) {
+		t.Errorf("got=[%v], want=[%v]", doc.Chapters, wantChapters)
+	}
+}
@@ -0,0 +1,241 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+	"textreader/internal/wrap"
+)
+
+func parseFB2(r io.Reader) (*xmlNode, error) {
+	// Embedded images are base64 blobs we have no use for.
+	root, err := parseXMLTree(r, "binary")
+	if err != nil {
+		return nil, err
+	}
+	book := root.child("FictionBook")
+	if book == nil {
+		return nil, fmt.Errorf("not a FictionBook document")
+	}
+	return book, nil
+}
+
+// fb2Layout turns the bodies of a FictionBook into lines, numbering footnotes as they are
+// referenced.
+type fb2Layout struct {
+	doc         Document
+	notes       map[string]string
+	noteNumbers map[string]int
+	noteOrder   []string
+}
+
+// ReadFB2 reads a FictionBook 2 document: sections, titles, epigraphs, poems and footnotes
+// become lines, and the title-info description becomes the book metadata.
+func ReadFB2(r io.Reader) (Document, error) {
+	book, err := parseFB2(r)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to parse fb2: %w", err)
+	}
+
+	l := &fb2Layout{
+		doc:         Document{Lines: []string{}, Chapters: []model.Chapter{}, Styles: map[int]string{}},
+		notes:       map[string]string{},
+		noteNumbers: map[string]int{},
+	}
+	if description := book.child("description"); description != nil {
+		if info := description.child("title-info"); info != nil {
+			l.doc.Metadata = fb2Metadata(info)
+		}
+	}
+
+	bodies := make([]*xmlNode, 0)
+	for _, c := range book.children {
+		if c.name != "body" {
+			continue
+		}
+		if name := c.attr("name"); name == "notes" || name == "comments" {
+			l.collectNotes(c)
+			continue
+		}
+		bodies = append(bodies, c)
+	}
+	for _, body := range bodies {
+		l.block(body, 0)
+	}
+	l.footnotes()
+	return l.doc, nil
+}
+
+func fb2Metadata(info *xmlNode) model.BookMetadata {
+	metadata := model.BookMetadata{
+		Title:    info.childText("book-title"),
+		Language: info.childText("lang"),
+	}
+	if author := info.child("author"); author != nil {
+		names := make([]string, 0, 3)
+		for _, part := range []string{"first-name", "middle-name", "last-name"} {
+			if name := author.childText(part); name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			names = append(names, author.childText("nickname"))
+		}
+		metadata.Author = strings.Join(names, " ")
+	}
+	return metadata
+}
+
+func (l *fb2Layout) collectNotes(body *xmlNode) {
+	for _, section := range body.children {
+		if section.name != "section" || section.attr("id") == "" {
+			continue
+		}
+		paragraphs := make([]string, 0)
+		for _, c := range section.children {
+			if c.name != "title" {
+				if p := collapseSpaces(c.plainText()); p != "" {
+					paragraphs = append(paragraphs, p)
+				}
+			}
+		}
+		l.notes[section.attr("id")] = strings.Join(paragraphs, " ")
+	}
+}
+
+func (l *fb2Layout) block(node *xmlNode, depth int) {
+	for _, c := range node.children {
+		switch c.name {
+		case "section":
+			l.block(c, depth+1)
+		case "title":
+			l.title(c, depth)
+		case "subtitle":
+			l.doc.styled(l.inline(c), markup.HeadingStyle)
+		case "epigraph":
+			l.quoted(c, "    ")
+		case "cite":
+			l.quoted(c, "│ ")
+		case "poem":
+			l.poem(c)
+		case "p":
+			l.doc.paragraph(l.inline(c), "", "")
+		case "empty-line":
+			l.doc.blank()
+		case "table":
+			l.table(c)
+		}
+	}
+}
+
+// title makes a heading; titles of the first two section levels are chapters.
+func (l *fb2Layout) title(node *xmlNode, depth int) {
+	title := ""
+	for _, p := range node.children {
+		s := collapseSpaces(l.inline(p))
+		switch {
+		case s == "":
+		case title == "":
+			title = s
+		case strings.ContainsAny(title[len(title)-1:], ".,;:!?"):
+			title += " " + s
+		default:
+			title += ". " + s
+		}
+	}
+	if title == "" {
+		return
+	}
+	l.doc.blank()
+	if depth >= 1 && depth <= 2 {
+		l.doc.Chapters = append(l.doc.Chapters, model.Chapter{Title: title, Line: len(l.doc.Lines)})
+	}
+	l.doc.styled(title, markup.HeadingStyle)
+}
+
+func (l *fb2Layout) quoted(node *xmlNode, indent string) {
+	for _, c := range node.children {
+		switch c.name {
+		case "text-author":
+			l.doc.paragraph("— "+l.inline(c), indent, indent)
+		case "poem":
+			l.poem(c)
+		case "p", "subtitle":
+			l.doc.paragraph(l.inline(c), indent, indent)
+		case "empty-line":
+			l.doc.blank()
+		}
+	}
+}
+
+func (l *fb2Layout) poem(node *xmlNode) {
+	for _, c := range node.children {
+		switch c.name {
+		case "title":
+			l.doc.styled(collapseSpaces(c.plainText()), markup.HeadingStyle)
+		case "epigraph":
+			l.quoted(c, "    ")
+		case "stanza":
+			l.doc.blank()
+			for _, v := range c.children {
+				if v.name == "v" {
+					for _, line := range wrap.Paragraph(l.inline(v), model.WrapWidth-2) {
+						l.doc.Lines = append(l.doc.Lines, "  "+line)
+					}
+				}
+			}
+		case "text-author":
+			l.doc.paragraph("— "+l.inline(c), "    ", "    ")
+		}
+	}
+}
+
+func (l *fb2Layout) table(node *xmlNode) {
+	l.doc.blank()
+	for _, row := range node.children {
+		if row.name != "tr" {
+			continue
+		}
+		cells := make([]string, 0)
+		for _, cell := range row.children {
+			if cell.name == "td" || cell.name == "th" {
+				cells = append(cells, collapseSpaces(l.inline(cell)))
+			}
+		}
+		l.doc.Lines = append(l.doc.Lines, strings.Join(cells, " | "))
+	}
+}
+
+// inline returns the text of a paragraph, replacing note links with their footnote number.
+func (l *fb2Layout) inline(node *xmlNode) string {
+	if node.name == "" {
+		return node.text
+	}
+	if node.name == "a" {
+		id := strings.TrimPrefix(node.attr("href"), "#")
+		if _, ok := l.notes[id]; ok || node.attr("type") == "note" {
+			n, seen := l.noteNumbers[id]
+			if !seen {
+				l.noteOrder = append(l.noteOrder, id)
+				n = len(l.noteOrder)
+				l.noteNumbers[id] = n
+			}
+			return fmt.Sprintf("[%d]", n)
+		}
+	}
+	var sb strings.Builder
+	for _, c := range node.children {
+		sb.WriteString(l.inline(c))
+	}
+	return sb.String()
+}
+
+func (l *fb2Layout) footnotes() {
+	notes := make([]string, 0, len(l.noteOrder))
+	for _, id := range l.noteOrder {
+		notes = append(notes, l.notes[id])
+	}
+	l.doc.notes(notes)
+}
@@ -0,0 +1,84 @@
+package file
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"textreader/internal/model"
+)
+
+func TestReadFB2(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<FictionBook xmlns="http://www.gribuser.ru/xml/fictionbook/2.0" xmlns:l="http://www.w3.org/1999/xlink">
+  <description>
+    <title-info>
+      <author><first-name>Jorge</first-name><middle-name>Luis</middle-name><last-name>Borges</last-name></author>
+      <book-title>Ficciones</book-title>
+      <lang>es</lang>
+    </title-info>
+  </description>
+  <body>
+    <title><p>Ficciones</p></title>
+    <section>
+      <title><p>Tlön, Uqbar,</p><p>Orbis Tertius</p></title>
+      <epigraph><p>Un espejo.</p><text-author>Bioy</text-author></epigraph>
+      <p>Debo a la conjunción<a l:href="#n1" type="note">1</a> de un espejo.</p>
+      <poem><stanza><v>verso uno</v><v>verso dos</v></stanza></poem>
+    </section>
+  </body>
+  <body name="notes">
+    <section id="n1"><title><p>1</p></title><p>Una nota.</p></section>
+  </body>
+  <binary id="cover.jpg" content-type="image/jpeg">AAAA</binary>
+</FictionBook>`
+
+	doc, err := ReadFB2(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLines := []string{
+		"Ficciones",
+		"",
+		"Tlön, Uqbar, Orbis Tertius",
+		"",
+		"    Un espejo.",
+		"",
+		"    — Bioy",
+		"",
+		"Debo a la conjunción[1] de un espejo.",
+		"",
+		"  verso uno",
+		"  verso dos",
+		"",
+		"Notes",
+		"",
+		"[1] Una nota.",
+	}
+	if !reflect.DeepEqual(doc.Lines, wantLines) {
+		t.Errorf("got=[%q], want=[%q]", doc.Lines, wantLines)
+	}
+
+	wantChapters := []model.Chapter{{Title: "Tlön, Uqbar, Orbis Tertius", Line: 2}, {Title: "Notes", Line: 13}}
+	if !reflect.DeepEqual(doc.Chapters, wantChapters) {
+		t.Errorf("got=[%v], want=[%v]", doc.Chapters, wantChapters)
+	}
+
+	wantMetadata := model.BookMetadata{Title: "Ficciones", Author: "Jorge Luis Borges", Language: "es"}
+	if doc.Metadata != wantMetadata {
+		t.Errorf("got=[%v], want=[%v]", doc.Metadata, wantMetadata)
+	}
+}
+
+func TestReadFB2Windows1251(t *testing.T) {
+	content := "<?xml version=\"1.0\" encoding=\"windows-1251\"?>\n" +
+		"<FictionBook><body><section><p>\xcf\xf0\xe8\xe2\xe5\xf2</p></section></body></FictionBook>"
+
+	doc, err := ReadFB2(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Привет"}; !reflect.DeepEqual(doc.Lines, want) {
+		t.Errorf("got=[%q], want=[%q]", doc.Lines, want)
+	}
+}
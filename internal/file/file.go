@@ -13,75 +13,130 @@ import (
 	"runtime"
 	"strings"
 	"textreader/internal/model"
+	"textreader/internal/progress"
+	"time"
 )
 
 type ProgressEntry struct {
-	FileName   string   `json:"file_name"`
-	From       int      `json:"from"`
-	To         int      `json:"to"`
-	Vocabulary []string `json:"vocabulary"`
+	FileName   string             `json:"file_name"`
+	From       int                `json:"from"`
+	To         int                `json:"to"`
+	Vocabulary []model.VocabEntry `json:"vocabulary"`
+	Title      string             `json:"title,omitempty"`
+	Author     string             `json:"author,omitempty"`
+	// Bookmarks are the named positions saved in the book.
+	Bookmarks []model.Bookmark `json:"bookmarks,omitempty"`
+	// PercentageDurations is the time it took to reach each percentage point of the book.
+	PercentageDurations map[int]time.Duration  `json:"percentage_durations,omitempty"`
+	Sessions            []model.ReadingSession `json:"sessions,omitempty"`
+	// Percentage is the furthest point of the book reached, 100 once finished.
+	Percentage int `json:"percentage,omitempty"`
 }
 
 func getProgressFilePath() string {
 	return filepath.Join(GetHomeDirectoryPath(runtime.GOOS), "ltbr", "progress.json")
 }
 
-func SaveStatus(fileName string, from, to int, state *model.AppState) error {
-	absPath, err := filepath.Abs(fileName)
+// LoadProgress reads the progress file, entries keyed as SaveStatus does. It is empty when
+// nothing has been saved yet.
+func LoadProgress() (map[string]ProgressEntry, error) {
+	data := make(map[string]ProgressEntry)
+	content, err := os.ReadFile(getProgressFilePath())
+	if os.IsNotExist(err) {
+		return data, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, fmt.Errorf("failed to read progress file: %w", err)
 	}
-	key := hashPath(absPath)
-	progressFile := getProgressFilePath()
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal progress: %w", err)
+	}
+	return data, nil
+}
 
-	data := make(map[string]ProgressEntry)
-	if _, err := os.Stat(progressFile); err == nil {
-		content, err := os.ReadFile(progressFile)
-		if err != nil {
-			return fmt.Errorf("failed to read progress file: %w", err)
-		}
-		if err := json.Unmarshal(content, &data); err != nil {
-			return fmt.Errorf("failed to unmarshal progress: %w", err)
-		}
+// SaveStatus stores the reading position for fileName. Entries are keyed by the path the user
+// opened, so compressed books keep the key of the archive and not of its decompressed content.
+// Books read from standard input have no path and are keyed by a hash of their content.
+func SaveStatus(fileName string, from, to int, state *model.AppState) error {
+	return updateProgress(fileName, state, func(entry *ProgressEntry) {
+		entry.From = from
+		entry.To = to
+		entry.Vocabulary = state.Vocabulary
+		entry.Title = state.Metadata.Title
+		entry.Author = state.Metadata.Author
+		entry.Bookmarks = state.Bookmarks
+	})
+}
+
+// SaveSession stores the reading statistics of fileName without saving the position, which
+// is only saved on request.
+func SaveSession(fileName string, state *model.AppState) error {
+	return updateProgress(fileName, state, func(*ProgressEntry) {})
+}
+
+// updateProgress rewrites the entry of fileName in the progress file with update, along with
+// the reading statistics, which are always kept up to date.
+func updateProgress(fileName string, state *model.AppState, update func(entry *ProgressEntry)) error {
+	absPath, key, err := progressKey(fileName, state)
+	if err != nil {
+		return err
+	}
+	data, err := LoadProgress()
+	if err != nil {
+		return err
 	}
 
-	data[key] = ProgressEntry{
-		FileName:   absPath,
-		From:       from,
-		To:         to,
-		Vocabulary: state.Vocabulary,
+	entry := data[key]
+	entry.FileName = absPath
+	entry.PercentageDurations = state.MinutesToReachNextPercentagePoint
+	entry.Sessions = state.AllSessions(time.Now())
+	percentage := progress.GetBookPercentage(state.To, state.BodyStart, state.BodyEnd)
+	entry.Percentage = max(entry.Percentage, state.CurrentPercentage, int(percentage))
+	update(&entry)
+	data[key] = entry
+	return writeProgress(data)
+}
+
+// UpdateVocabEntry saves the word of entry in the vocabulary of the book of the progress file
+// entry key, e.g. after reviewing it.
+func UpdateVocabEntry(key string, entry model.VocabEntry) error {
+	data, err := LoadProgress()
+	if err != nil {
+		return err
+	}
+	book, ok := data[key]
+	if !ok {
+		return fmt.Errorf("no book with key %s in the progress file", key)
 	}
+	for i := range book.Vocabulary {
+		if book.Vocabulary[i].Word == entry.Word {
+			book.Vocabulary[i] = entry
+			data[key] = book
+			return writeProgress(data)
+		}
+	}
+	return fmt.Errorf("'%s' is not in the vocabulary of %s", entry.Word, book.FileName)
+}
 
+func writeProgress(data map[string]ProgressEntry) error {
 	content, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal progress: %w", err)
 	}
-	if err := os.WriteFile(progressFile, content, 0644); err != nil {
+	if err := os.WriteFile(getProgressFilePath(), content, 0644); err != nil {
 		return fmt.Errorf("failed to write progress file: %w", err)
 	}
 	return nil
 }
 
 func GetFileNameFromLatest(filePath string, state *model.AppState) (model.LatestFile, error) {
-	absPath, err := filepath.Abs(filePath)
+	absPath, key, err := progressKey(filePath, state)
 	if err != nil {
-		return model.LatestFile{}, fmt.Errorf("failed to get absolute path: %w", err)
+		return model.LatestFile{}, err
 	}
-	key := hashPath(absPath)
-	progressFile := getProgressFilePath()
-
-	if _, err := os.Stat(progressFile); os.IsNotExist(err) {
-		return model.LatestFile{FileName: absPath, From: 0, To: state.Advance}, nil
-	}
-
-	content, err := os.ReadFile(progressFile)
+	data, err := LoadProgress()
 	if err != nil {
-		return model.LatestFile{}, fmt.Errorf("failed to read progress file: %w", err)
-	}
-
-	data := make(map[string]ProgressEntry)
-	if err := json.Unmarshal(content, &data); err != nil {
-		return model.LatestFile{}, fmt.Errorf("failed to unmarshal progress: %w", err)
+		return model.LatestFile{}, err
 	}
 
 	entry, ok := data[key]
@@ -90,6 +145,16 @@ func GetFileNameFromLatest(filePath string, state *model.AppState) (model.Latest
 	}
 
 	state.Vocabulary = entry.Vocabulary // Load vocabulary into state
+	if entry.Bookmarks != nil {
+		state.Bookmarks = entry.Bookmarks
+	}
+	state.Sessions = entry.Sessions
+	// Percentage points already reached in this run win over the ones of earlier runs.
+	for percentage, duration := range entry.PercentageDurations {
+		if _, ok := state.MinutesToReachNextPercentagePoint[percentage]; !ok {
+			state.MinutesToReachNextPercentagePoint[percentage] = duration
+		}
+	}
 	return model.LatestFile{
 		FileName: entry.FileName,
 		From:     entry.From,
@@ -97,6 +162,33 @@ func GetFileNameFromLatest(filePath string, state *model.AppState) (model.Latest
 	}, nil
 }
 
+// ProgressKey returns the key of the entry of fileName in the progress file.
+func ProgressKey(fileName string, state *model.AppState) (string, error) {
+	_, key, err := progressKey(fileName, state)
+	return key, err
+}
+
+// progressKey returns the name stored in the progress file for fileName and the key of its entry.
+func progressKey(fileName string, state *model.AppState) (string, string, error) {
+	if fileName == model.StdinFileName {
+		return fileName, hashContent(state.FileContent), nil
+	}
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return absPath, hashPath(absPath), nil
+}
+
+func hashContent(lines []string) string {
+	h := md5.New()
+	for _, line := range lines {
+		_, _ = io.WriteString(h, line)
+		_, _ = io.WriteString(h, "\n")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func hashPath(path string) string {
 	h := md5.Sum([]byte(path))
 	return hex.EncodeToString(h[:])
@@ -167,8 +259,11 @@ func GetHomeDirectoryPath(opSystem string) string {
 }
 
 func GetDirectoryNameForFile(dirType, fileName string) string {
-	absoluteFilePath, _ := filepath.Abs(fileName)
-	baseFileName := path.Base(absoluteFilePath)
+	baseFileName := "stdin"
+	if fileName != model.StdinFileName {
+		absoluteFilePath, _ := filepath.Abs(fileName)
+		baseFileName = path.Base(absoluteFilePath)
+	}
 
 	baseFileName = SanitizeFileName(baseFileName)
 	notesDir := filepath.Join(GetHomeDirectoryPath(runtime.GOOS), "ltbr", dirType, baseFileName)
@@ -0,0 +1,177 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"textreader/internal/model"
+	"time"
+)
+
+func TestProgressKey(t *testing.T) {
+	state := model.NewAppState()
+	state.FileContent = []string{"hola", "mundo"}
+
+	name, key, err := progressKey(model.StdinFileName, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != model.StdinFileName {
+		t.Errorf("got=[%s], want=[%s]", name, model.StdinFileName)
+	}
+
+	state.FileContent = []string{"hola", "mundo", "cruel"}
+	if _, other, _ := progressKey(model.StdinFileName, state); other == key {
+		t.Errorf("different piped content should not share a progress key")
+	}
+
+	if _, pathKey, _ := progressKey("/tmp/libro.txt", state); pathKey != hashPath("/tmp/libro.txt") {
+		t.Errorf("got=[%s], want=[%s]", pathKey, hashPath("/tmp/libro.txt"))
+	}
+}
+
+func TestSaveStatusKeepsBookmarks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "ltbr"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	state := model.NewAppState()
+	state.Bookmarks = []model.Bookmark{{Name: "El duelo", Line: 120}, {Name: "Line 300", Line: 300}}
+	if err := SaveStatus("/tmp/libro.txt", 100, 140, state); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := model.NewAppState()
+	latest, err := GetFileNameFromLatest("/tmp/libro.txt", restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.From != 100 || latest.To != 140 {
+		t.Errorf("got from=%d to=%d, want from=100 to=140", latest.From, latest.To)
+	}
+	if !reflect.DeepEqual(restored.Bookmarks, state.Bookmarks) {
+		t.Errorf("Bookmarks = %v, want %v", restored.Bookmarks, state.Bookmarks)
+	}
+}
+
+func TestSaveSessionMergesHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "ltbr"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	first := model.NewAppState()
+	if err := SaveStatus("/tmp/libro.txt", 100, 140, first); err != nil {
+		t.Fatal(err)
+	}
+	first.Session = model.ReadingSession{Start: time.Now().Add(-time.Hour), LinesRead: 400}
+	first.MinutesToReachNextPercentagePoint[1] = 2 * time.Minute
+	if err := SaveSession("/tmp/libro.txt", first); err != nil {
+		t.Fatal(err)
+	}
+
+	second := model.NewAppState()
+	latest, err := GetFileNameFromLatest("/tmp/libro.txt", second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.From != 100 {
+		t.Errorf("From = %d, saving a session should keep the saved position", latest.From)
+	}
+	second.Session = model.ReadingSession{Start: time.Now().Add(-time.Minute), LinesRead: 30}
+	second.MinutesToReachNextPercentagePoint[2] = 3 * time.Minute
+	if err := SaveSession("/tmp/libro.txt", second); err != nil {
+		t.Fatal(err)
+	}
+	// A session where nothing was read is left out.
+	third := model.NewAppState()
+	if _, err := GetFileNameFromLatest("/tmp/libro.txt", third); err != nil {
+		t.Fatal(err)
+	}
+	third.Session = model.ReadingSession{Start: time.Now()}
+	if err := SaveSession("/tmp/libro.txt", third); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := model.NewAppState()
+	if _, err := GetFileNameFromLatest("/tmp/libro.txt", restored); err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.Sessions) != 2 || restored.Sessions[0].LinesRead != 400 || restored.Sessions[1].LinesRead != 30 {
+		t.Fatalf("Sessions = %+v, want the two sessions read", restored.Sessions)
+	}
+	if restored.Sessions[0].End.Before(restored.Sessions[0].Start) {
+		t.Errorf("session ends at %v before starting at %v", restored.Sessions[0].End, restored.Sessions[0].Start)
+	}
+	want := map[int]time.Duration{1: 2 * time.Minute, 2: 3 * time.Minute}
+	if !reflect.DeepEqual(restored.MinutesToReachNextPercentagePoint, want) {
+		t.Errorf("MinutesToReachNextPercentagePoint = %v, want %v", restored.MinutesToReachNextPercentagePoint, want)
+	}
+}
+
+func TestLoadMigratesVocabulary(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "ltbr"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Vocabularies used to be saved as bare words.
+	content := `{"` + hashPath("/tmp/libro.txt") + `": {"file_name": "/tmp/libro.txt", "from": 10, "to": 50, "vocabulary": ["hidalgo", "rocín"]}}`
+	if err := os.WriteFile(getProgressFilePath(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state := model.NewAppState()
+	if _, err := GetFileNameFromLatest("/tmp/libro.txt", state); err != nil {
+		t.Fatal(err)
+	}
+	want := []model.VocabEntry{{Word: "hidalgo", Line: -1}, {Word: "rocín", Line: -1}}
+	if !reflect.DeepEqual(state.Vocabulary, want) {
+		t.Fatalf("Vocabulary = %+v, want %+v", state.Vocabulary, want)
+	}
+
+	added := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	state.Vocabulary = append(state.Vocabulary, model.VocabEntry{Word: "adarga", Sentence: "lanza en astillero, adarga antigua", Line: 12, Added: added})
+	if err := SaveStatus("/tmp/libro.txt", 10, 50, state); err != nil {
+		t.Fatal(err)
+	}
+	// Words without a date, like the migrated ones, are saved without one.
+	saved, err := os.ReadFile(getProgressFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(saved), `"added"`); got != 1 {
+		t.Errorf("got=[%d], want=[%d] added dates in %s", got, 1, saved)
+	}
+	restored := model.NewAppState()
+	if _, err := GetFileNameFromLatest("/tmp/libro.txt", restored); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.Vocabulary, state.Vocabulary) {
+		t.Errorf("Vocabulary = %+v, want %+v", restored.Vocabulary, state.Vocabulary)
+	}
+
+	// Words never reviewed have no due date.
+	if strings.Contains(string(saved), `"due"`) {
+		t.Errorf("got=[%s], want=[no due dates]", saved)
+	}
+	reviewed := model.VocabEntry{Word: "rocín", Line: -1, Ease: 2.5, Interval: 1, Repetitions: 1, Due: added.AddDate(0, 0, 1)}
+	if err := UpdateVocabEntry(hashPath("/tmp/libro.txt"), reviewed); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := LoadProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entries[hashPath("/tmp/libro.txt")].Vocabulary[1]; !reflect.DeepEqual(got, reviewed) {
+		t.Errorf("UpdateVocabEntry() saved %+v, want %+v", got, reviewed)
+	}
+	if err := UpdateVocabEntry(hashPath("/tmp/libro.txt"), model.VocabEntry{Word: "yelmo"}); err == nil {
+		t.Error("UpdateVocabEntry() expected an error for a word not in the vocabulary")
+	}
+}
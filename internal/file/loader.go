@@ -0,0 +1,266 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+)
+
+// Loader turns the raw content of a book into a Document: its lines, metadata and
+// structure markers such as chapters and page breaks.
+type Loader interface {
+	Load(data []byte, opts LoadOptions) (Document, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface.
+type LoaderFunc func(data []byte, opts LoadOptions) (Document, error)
+
+// Load calls f(data, opts).
+func (f LoaderFunc) Load(data []byte, opts LoadOptions) (Document, error) {
+	return f(data, opts)
+}
+
+// Format is a document format known to the reader.
+type Format struct {
+	// Name selects the format explicitly, e.g. with the -format flag.
+	Name string
+	// Extensions are matched against the file name, including the dot.
+	Extensions []string
+	// Sniff reports whether data looks like this format, it may be nil.
+	Sniff  func(data []byte) bool
+	Loader Loader
+}
+
+// TextFormat is used when no other format matches.
+const TextFormat = "text"
+
+// Registry is the set of formats books can be read with.
+type Registry struct {
+	formats []Format
+}
+
+// NewRegistry returns a registry with the formats the reader knows by itself.
+func NewRegistry() *Registry {
+	return &Registry{formats: []Format{
+		{Name: "pdf", Extensions: []string{".pdf"}, Sniff: isPDF, Loader: LoaderFunc(loadPDF)},
+		{Name: "epub", Extensions: []string{".epub"}, Sniff: zipMimetype("application/epub+zip"), Loader: LoaderFunc(loadEPUB)},
+		{Name: "odt", Extensions: []string{".odt"}, Sniff: zipMimetype("application/vnd.oasis.opendocument.text"), Loader: LoaderFunc(loadODT)},
+		{Name: "docx", Extensions: []string{".docx"}, Sniff: isDOCX, Loader: LoaderFunc(loadDOCX)},
+		// FB2 declares its own encoding in the XML prolog.
+		{Name: "fb2", Extensions: []string{".fb2"}, Sniff: isFB2, Loader: LoaderFunc(loadFB2)},
+		{Name: "html", Extensions: []string{".html", ".htm", ".xhtml"}, Sniff: isHTML, Loader: LoaderFunc(loadHTML)},
+		{Name: "markdown", Extensions: []string{".md", ".markdown"}, Loader: LoaderFunc(loadMarkdown)},
+		{Name: TextFormat, Extensions: []string{".txt"}, Loader: LoaderFunc(loadText)},
+	}}
+}
+
+// Register adds a format. Formats registered later win over earlier ones for the same name or
+// extension, so a converter can replace a built-in loader.
+func (r *Registry) Register(format Format) {
+	r.formats = append([]Format{format}, r.formats...)
+}
+
+// Find chooses the format of a book: by explicit name when one is given, then by the
+// extension of the file name, then by sniffing its content. Plain text is the fallback.
+func (r *Registry) Find(name, explicit string, data []byte) (Format, error) {
+	if explicit != "" {
+		for _, f := range r.formats {
+			if strings.EqualFold(f.Name, explicit) {
+				return f, nil
+			}
+		}
+		return Format{}, fmt.Errorf("unknown format %q, known formats are %s", explicit, strings.Join(r.Names(), ", "))
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, f := range r.formats {
+		for _, e := range f.Extensions {
+			if ext != "" && strings.EqualFold(e, ext) {
+				return f, nil
+			}
+		}
+	}
+	for _, f := range r.formats {
+		if f.Sniff != nil && f.Sniff(data) {
+			return f, nil
+		}
+	}
+	for _, f := range r.formats {
+		if f.Name == TextFormat {
+			return f, nil
+		}
+	}
+	return Format{}, fmt.Errorf("no format found for %s", name)
+}
+
+// Names lists the names of the formats.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.formats))
+	seen := map[string]bool{}
+	for _, f := range r.formats {
+		if !seen[f.Name] {
+			seen[f.Name] = true
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+func isPDF(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-"))
+}
+
+// zipMimetype matches containers that start with a stored "mimetype" entry, as EPUB and
+// OpenDocument files do.
+func zipMimetype(mimetype string) func(data []byte) bool {
+	return func(data []byte) bool {
+		return bytes.HasPrefix(data, zipMagic) && len(data) > 30 &&
+			bytes.HasPrefix(data[30:], []byte("mimetype"+mimetype))
+	}
+}
+
+func isDOCX(data []byte) bool {
+	return bytes.HasPrefix(data, zipMagic) && bytes.Contains(data, []byte("word/document.xml"))
+}
+
+func isFB2(data []byte) bool {
+	return bytes.Contains(head(data, 1024), []byte("<FictionBook"))
+}
+
+func isHTML(data []byte) bool {
+	return strings.HasPrefix(http.DetectContentType(data), "text/html")
+}
+
+func head(data []byte, n int) []byte {
+	if len(data) > n {
+		return data[:n]
+	}
+	return data
+}
+
+func loadPDF(data []byte, _ LoadOptions) (Document, error) {
+	return ReadPDF(data)
+}
+
+func loadEPUB(data []byte, _ LoadOptions) (Document, error) {
+	return ReadEPUB(bytes.NewReader(data), int64(len(data)))
+}
+
+func loadODT(data []byte, _ LoadOptions) (Document, error) {
+	return ReadODT(bytes.NewReader(data), int64(len(data)))
+}
+
+func loadDOCX(data []byte, _ LoadOptions) (Document, error) {
+	return ReadDOCX(bytes.NewReader(data), int64(len(data)))
+}
+
+func loadFB2(data []byte, _ LoadOptions) (Document, error) {
+	return ReadFB2(bytes.NewReader(data))
+}
+
+func loadHTML(data []byte, opts LoadOptions) (Document, error) {
+	content, err := DecodeToUTF8(data, opts.Encoding)
+	if err != nil {
+		return Document{}, err
+	}
+	rendered, err := markup.RenderHTML(strings.NewReader(content), model.WrapWidth)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to parse html: %w", err)
+	}
+	return renderedDocument(rendered), nil
+}
+
+func loadMarkdown(data []byte, opts LoadOptions) (Document, error) {
+	content, err := DecodeToUTF8(data, opts.Encoding)
+	if err != nil {
+		return Document{}, err
+	}
+	return renderedDocument(markup.RenderMarkdown(content, model.WrapWidth)), nil
+}
+
+func loadText(data []byte, opts LoadOptions) (Document, error) {
+	content, err := DecodeToUTF8(data, opts.Encoding)
+	if err != nil {
+		return Document{}, err
+	}
+	lines, err := ReadLines(strings.NewReader(content))
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{Lines: lines}, nil
+}
+
+// CommandLoader runs an external converter and loads what it writes to standard output with
+// the Output format ("text" when empty). The book is piped to the command on standard input,
+// unless an argument contains "{file}": then it is written to a temporary file with the
+// Extension given and "{file}" is replaced by its path.
+type CommandLoader struct {
+	Command   []string
+	Output    string
+	Extension string
+}
+
+// maxNestedConversions is how many times the output of a converter can go through another one,
+// so that converters whose outputs lead to each other don't run forever.
+const maxNestedConversions = 1
+
+// Load runs the converter.
+func (c CommandLoader) Load(data []byte, opts LoadOptions) (Document, error) {
+	if len(c.Command) == 0 {
+		return Document{}, fmt.Errorf("converter has no command")
+	}
+	if opts.conversions > maxNestedConversions {
+		return Document{}, fmt.Errorf("too many nested conversions running %s, the output of a converter can go through one more at most", c.Command[0])
+	}
+
+	args := make([]string, len(c.Command))
+	copy(args, c.Command)
+	usesFile := false
+	for _, arg := range args {
+		usesFile = usesFile || strings.Contains(arg, "{file}")
+	}
+	if usesFile {
+		tmp, err := os.CreateTemp("", "ltbr-*"+c.Extension)
+		if err != nil {
+			return Document{}, fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		_, err = tmp.Write(data)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return Document{}, fmt.Errorf("failed to write temporary file: %w", err)
+		}
+		for i := range args {
+			args[i] = strings.ReplaceAll(args[i], "{file}", tmp.Name())
+		}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	if !usesFile {
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return Document{}, fmt.Errorf("failed to run %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	output := c.Output
+	if output == "" {
+		output = TextFormat
+	}
+	format, err := opts.registry().Find("", output, stdout.Bytes())
+	if err != nil {
+		return Document{}, err
+	}
+	opts.conversions++
+	return format.Loader.Load(stdout.Bytes(), opts)
+}
@@ -0,0 +1,95 @@
+package file
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryFind(t *testing.T) {
+	tests := []struct {
+		name, explicit string
+		data           string
+		want           string
+	}{
+		{name: "book.pdf", data: "anything", want: "pdf"},
+		{name: "book.MD", data: "# Title", want: "markdown"},
+		{name: "-", data: "%PDF-1.4\n", want: "pdf"},
+		{name: "-", data: "<!DOCTYPE html><html><body>hi</body></html>", want: "html"},
+		{name: "-", data: `<?xml version="1.0"?><FictionBook>`, want: "fb2"},
+		{name: "-", data: "PK\x03\x04" + string(make([]byte, 26)) + "mimetypeapplication/epub+zip", want: "epub"},
+		{name: "notes", data: "plain words", want: TextFormat},
+		{name: "book.pdf", explicit: "text", data: "%PDF-1.4", want: TextFormat},
+	}
+
+	registry := NewRegistry()
+	for _, tc := range tests {
+		got, err := registry.Find(tc.name, tc.explicit, []byte(tc.data))
+		if err != nil {
+			t.Errorf("Find(%q, %q) error = %v", tc.name, tc.explicit, err)
+			continue
+		}
+		if got.Name != tc.want {
+			t.Errorf("got=[%s], want=[%s]", got.Name, tc.want)
+		}
+	}
+
+	if _, err := registry.Find("book.txt", "mobi", nil); err == nil {
+		t.Error("Find() expected an error for an unknown format")
+	}
+
+	registry.Register(Format{Name: "mobi", Extensions: []string{".mobi", ".txt"}, Loader: CommandLoader{Command: []string{"cat"}}})
+	if got, err := registry.Find("book.txt", "", nil); err != nil || got.Name != "mobi" {
+		t.Errorf("got=[%s], want=[%s]", got.Name, "mobi")
+	}
+	if got, err := NewRegistry().Find("book.txt", "", nil); err != nil || got.Name != TextFormat {
+		t.Errorf("got=[%s], want=[%s]", got.Name, TextFormat)
+	}
+}
+
+func TestCommandLoader(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	piped := CommandLoader{Command: []string{"sh", "-c", "tr a-z A-Z"}}
+	doc, err := piped.Load([]byte("one\ntwo"), LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ONE", "TWO"}; !reflect.DeepEqual(doc.Lines, want) {
+		t.Errorf("got=[%q], want=[%q]", doc.Lines, want)
+	}
+
+	withFile := CommandLoader{Command: []string{"sh", "-c", `printf '# Title\n\n%s\n' "$(cat "$0")"`, "{file}"}, Output: "markdown", Extension: ".xyz"}
+	doc, err = withFile.Load([]byte("body"), LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Title", "", "body"}; !reflect.DeepEqual(doc.Lines, want) {
+		t.Errorf("got=[%q], want=[%q]", doc.Lines, want)
+	}
+
+	failing := CommandLoader{Command: []string{"sh", "-c", "echo broken >&2; exit 3"}}
+	if _, err := failing.Load(nil, LoadOptions{}); err == nil {
+		t.Error("Load() expected an error from a failing command")
+	}
+
+	// The output of a converter can go through another one, but no further.
+	registry := NewRegistry()
+	registry.Register(Format{Name: "upper", Loader: piped})
+	registry.Register(Format{Name: "outer", Loader: CommandLoader{Command: []string{"cat"}, Output: "upper"}})
+	registry.Register(Format{Name: "loop", Loader: CommandLoader{Command: []string{"cat"}, Output: "loop"}})
+	nested, _ := registry.Find("", "outer", nil)
+	doc, err = nested.Loader.Load([]byte("one"), LoadOptions{Registry: registry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ONE"}; !reflect.DeepEqual(doc.Lines, want) {
+		t.Errorf("got=[%q], want=[%q]", doc.Lines, want)
+	}
+	loop, _ := registry.Find("", "loop", nil)
+	if _, err := loop.Loader.Load([]byte("one"), LoadOptions{Registry: registry}); err == nil {
+		t.Error("Load() expected an error from converters leading to each other")
+	}
+}
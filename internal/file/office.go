@@ -0,0 +1,498 @@
+package file
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+)
+
+// titleLevel is the heading level given to Title and Subtitle paragraphs: they are styled
+// as headings but do not start a chapter.
+const titleLevel = 10
+
+// officeLayout holds what the DOCX and ODT readers have in common: headings, paragraphs
+// split by manual line breaks, lists and footnotes collected for the end of the document.
+type officeLayout struct {
+	doc    Document
+	notes  []string
+	inList bool
+}
+
+func newOfficeLayout() *officeLayout {
+	return &officeLayout{doc: Document{Lines: []string{}, Chapters: []model.Chapter{}, Styles: map[int]string{}}}
+}
+
+// note records a footnote and returns its marker.
+func (l *officeLayout) note(text string) string {
+	l.notes = append(l.notes, collapseSpaces(text))
+	return fmt.Sprintf("[%d]", len(l.notes))
+}
+
+// heading adds a heading; levels 1 and 2 are chapters.
+func (l *officeLayout) heading(level int, title string) {
+	l.inList = false
+	title = collapseSpaces(title)
+	if title == "" {
+		return
+	}
+	l.doc.blank()
+	if level >= 1 && level <= 2 {
+		l.doc.Chapters = append(l.doc.Chapters, model.Chapter{Title: title, Line: len(l.doc.Lines)})
+	}
+	l.doc.styled(title, markup.HeadingStyle)
+}
+
+func (l *officeLayout) paragraph(s, first, rest string) {
+	l.inList = false
+	for i, part := range strings.Split(s, "\n") {
+		if i == 0 {
+			l.doc.paragraph(part, first, rest)
+		} else {
+			l.doc.lines(part, rest, rest)
+		}
+	}
+}
+
+// listItem adds a bulleted paragraph, consecutive items are not separated by blank lines.
+func (l *officeLayout) listItem(s string, depth int) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	if !l.inList {
+		l.doc.blank()
+	}
+	l.inList = true
+	indent := strings.Repeat("  ", depth)
+	for i, part := range strings.Split(s, "\n") {
+		if i == 0 {
+			l.doc.lines(part, indent+"• ", indent+"  ")
+		} else {
+			l.doc.lines(part, indent+"  ", indent+"  ")
+		}
+	}
+}
+
+func (l *officeLayout) tableRow(cells []string) {
+	if !l.inList {
+		l.doc.blank()
+	}
+	// Rows are kept together like list items.
+	l.inList = true
+	l.doc.lines(strings.Join(cells, " | "), "", "  ")
+}
+
+func (l *officeLayout) finish() Document {
+	l.doc.notes(l.notes)
+	return l.doc
+}
+
+// classifyStyle tells how a paragraph style is laid out from its name, e.g. "heading 2" in
+// Word or "Heading_20_2" in OpenDocument. It returns 0 for styles that are not headings.
+func classifyStyle(name string) (level int, quote bool) {
+	name = strings.ToLower(strings.ReplaceAll(name, "_20_", " "))
+	switch {
+	case name == "title" || name == "subtitle":
+		return titleLevel, false
+	case strings.HasPrefix(name, "heading "):
+		if n, err := strconv.Atoi(strings.TrimPrefix(name, "heading ")); err == nil && n > 0 {
+			return n, false
+		}
+	case strings.Contains(name, "quot"):
+		return 0, true
+	}
+	return 0, false
+}
+
+// officeMetadata reads the Dublin Core properties of docProps/core.xml or meta.xml.
+func officeMetadata(meta *xmlNode) model.BookMetadata {
+	metadata := model.BookMetadata{
+		Title:    meta.childText("title"),
+		Author:   meta.childText("creator"),
+		Language: meta.childText("language"),
+	}
+	if metadata.Author == "" {
+		metadata.Author = meta.childText("initial-creator")
+	}
+	return metadata
+}
+
+func readZipTree(zr *zip.Reader, name string) (*xmlNode, error) {
+	f, err := openZipFile(zr, name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, err := parseXMLTree(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return root, nil
+}
+
+// docxStyle is a paragraph style of word/styles.xml.
+type docxStyle struct {
+	name, basedOn string
+	outline       int
+}
+
+type docxLayout struct {
+	*officeLayout
+	styles    map[string]docxStyle
+	footnotes map[string]string
+}
+
+// ReadDOCX reads a Word document: paragraphs, headings, lists, tables, footnotes and
+// endnotes, in the order of word/document.xml.
+func ReadDOCX(r io.ReaderAt, size int64) (Document, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to open docx: %w", err)
+	}
+	root, err := readZipTree(zr, "word/document.xml")
+	if err != nil {
+		return Document{}, err
+	}
+	body := root.child("document")
+	if body != nil {
+		body = body.child("body")
+	}
+	if body == nil {
+		return Document{}, fmt.Errorf("failed to parse docx: missing document body")
+	}
+
+	l := &docxLayout{officeLayout: newOfficeLayout(), styles: map[string]docxStyle{}, footnotes: map[string]string{}}
+	if styles, err := readZipTree(zr, "word/styles.xml"); err == nil && styles.child("styles") != nil {
+		l.loadStyles(styles.child("styles"))
+	}
+	for _, kind := range []string{"footnote", "endnote"} {
+		if notes, err := readZipTree(zr, "word/"+kind+"s.xml"); err == nil && notes.child(kind+"s") != nil {
+			l.loadNotes(notes.child(kind+"s"), kind)
+		}
+	}
+	if core, err := readZipTree(zr, "docProps/core.xml"); err == nil && core.child("coreProperties") != nil {
+		l.doc.Metadata = officeMetadata(core.child("coreProperties"))
+	}
+
+	l.body(body)
+	return l.finish(), nil
+}
+
+func (l *docxLayout) loadStyles(styles *xmlNode) {
+	for _, s := range styles.children {
+		if s.name != "style" || s.attr("type") != "paragraph" {
+			continue
+		}
+		style := docxStyle{outline: -1}
+		if name := s.child("name"); name != nil {
+			style.name = name.attr("val")
+		}
+		if basedOn := s.child("basedOn"); basedOn != nil {
+			style.basedOn = basedOn.attr("val")
+		}
+		if props := s.child("pPr"); props != nil {
+			style.outline = outlineLevel(props)
+		}
+		l.styles[s.attr("styleId")] = style
+	}
+}
+
+// loadNotes reads the footnotes (or endnotes) referenced from the text by id.
+func (l *docxLayout) loadNotes(notes *xmlNode, kind string) {
+	for _, n := range notes.children {
+		if n.name != kind || (n.attr("type") != "" && n.attr("type") != "normal") {
+			continue
+		}
+		paragraphs := make([]string, 0)
+		for _, p := range n.children {
+			if p.name == "p" {
+				paragraphs = append(paragraphs, strings.ReplaceAll(l.inline(p), "\n", " "))
+			}
+		}
+		l.footnotes[kind+":"+n.attr("id")] = strings.Join(paragraphs, " ")
+	}
+}
+
+func outlineLevel(props *xmlNode) int {
+	if outline := props.child("outlineLvl"); outline != nil {
+		if n, err := strconv.Atoi(outline.attr("val")); err == nil && n < 9 {
+			return n
+		}
+	}
+	return -1
+}
+
+// paragraphStyle follows the style of a paragraph (and the styles it is based on) to find
+// out whether it is a heading or a quote.
+func (l *docxLayout) paragraphStyle(props *xmlNode) (level int, quote bool) {
+	if props == nil {
+		return 0, false
+	}
+	if outline := outlineLevel(props); outline >= 0 {
+		return outline + 1, false
+	}
+	id := ""
+	if style := props.child("pStyle"); style != nil {
+		id = style.attr("val")
+	}
+	for i := 0; i < 10 && id != ""; i++ {
+		style, ok := l.styles[id]
+		if !ok {
+			// Documents without styles.xml still use the built-in ids, e.g. "Heading1".
+			return classifyStyle(strings.Replace(id, "Heading", "heading ", 1))
+		}
+		if level, quote := classifyStyle(style.name); level > 0 || quote {
+			return level, quote
+		}
+		if style.outline >= 0 {
+			return style.outline + 1, false
+		}
+		id = style.basedOn
+	}
+	return 0, false
+}
+
+func (l *docxLayout) body(node *xmlNode) {
+	for _, c := range node.children {
+		switch c.name {
+		case "p":
+			l.docxParagraph(c)
+		case "tbl":
+			l.table(c)
+		case "sdt":
+			// Content controls, e.g. a table of contents.
+			if content := c.child("sdtContent"); content != nil {
+				l.body(content)
+			}
+		}
+	}
+}
+
+func (l *docxLayout) docxParagraph(p *xmlNode) {
+	s := l.inline(p)
+	props := p.child("pPr")
+	level, quote := l.paragraphStyle(props)
+	switch {
+	case level > 0:
+		l.heading(level, s)
+	case props != nil && props.child("numPr") != nil:
+		depth := 0
+		if ilvl := props.child("numPr").child("ilvl"); ilvl != nil {
+			depth, _ = strconv.Atoi(ilvl.attr("val"))
+		}
+		l.listItem(s, depth)
+	case quote:
+		l.paragraph(s, "│ ", "│ ")
+	default:
+		l.paragraph(s, "", "")
+	}
+}
+
+func (l *docxLayout) table(tbl *xmlNode) {
+	for _, row := range tbl.children {
+		if row.name != "tr" {
+			continue
+		}
+		cells := make([]string, 0)
+		for _, cell := range row.children {
+			if cell.name != "tc" {
+				continue
+			}
+			paragraphs := make([]string, 0)
+			for _, p := range cell.children {
+				if p.name == "p" {
+					paragraphs = append(paragraphs, collapseSpaces(l.inline(p)))
+				}
+			}
+			cells = append(cells, strings.Join(paragraphs, " "))
+		}
+		l.tableRow(cells)
+	}
+	l.inList = false
+}
+
+// inline returns the text of a paragraph. Runs keep their text in <w:t>, everything else
+// in between (properties, field codes, deleted text) is left out.
+func (l *docxLayout) inline(node *xmlNode) string {
+	switch node.name {
+	case "t":
+		return node.plainText()
+	case "tab":
+		return " "
+	case "br", "cr":
+		return "\n"
+	case "noBreakHyphen":
+		return "-"
+	case "footnoteReference", "endnoteReference":
+		kind := strings.TrimSuffix(node.name, "Reference")
+		return l.note(l.footnotes[kind+":"+node.attr("id")])
+	case "", "pPr", "rPr", "delText", "instrText", "footnoteRef", "endnoteRef":
+		return ""
+	}
+	var sb strings.Builder
+	for _, c := range node.children {
+		sb.WriteString(l.inline(c))
+	}
+	return sb.String()
+}
+
+type odtLayout struct {
+	*officeLayout
+	// parents maps the automatic styles of content.xml to the named style they derive from.
+	parents map[string]string
+}
+
+// ReadODT reads an OpenDocument text: paragraphs, headings, lists, tables and notes of
+// content.xml.
+func ReadODT(r io.ReaderAt, size int64) (Document, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to open odt: %w", err)
+	}
+	root, err := readZipTree(zr, "content.xml")
+	if err != nil {
+		return Document{}, err
+	}
+	content := root.child("document-content")
+	var body *xmlNode
+	if content != nil && content.child("body") != nil {
+		body = content.child("body").child("text")
+	}
+	if body == nil {
+		return Document{}, fmt.Errorf("failed to parse odt: missing office:text body")
+	}
+
+	l := &odtLayout{officeLayout: newOfficeLayout(), parents: map[string]string{}}
+	if styles := content.child("automatic-styles"); styles != nil {
+		for _, s := range styles.children {
+			if s.name == "style" && s.attr("parent-style-name") != "" {
+				l.parents[s.attr("name")] = s.attr("parent-style-name")
+			}
+		}
+	}
+	if meta, err := readZipTree(zr, "meta.xml"); err == nil {
+		if doc := meta.child("document-meta"); doc != nil && doc.child("meta") != nil {
+			l.doc.Metadata = officeMetadata(doc.child("meta"))
+		}
+	}
+
+	l.block(body)
+	return l.finish(), nil
+}
+
+func (l *odtLayout) block(node *xmlNode) {
+	for _, c := range node.children {
+		switch c.name {
+		case "h":
+			level, err := strconv.Atoi(c.attr("outline-level"))
+			if err != nil || level < 1 {
+				level = 1
+			}
+			l.heading(level, l.inline(c))
+		case "p":
+			level, quote := classifyStyle(l.styleName(c.attr("style-name")))
+			switch {
+			case level > 0:
+				l.heading(level, l.inline(c))
+			case quote:
+				l.paragraph(l.inline(c), "│ ", "│ ")
+			default:
+				l.paragraph(l.inline(c), "", "")
+			}
+		case "list":
+			l.list(c, 0)
+			l.inList = false
+		case "table":
+			l.table(c)
+		case "section", "table-of-content", "index-body", "alphabetical-index", "illustration-index":
+			l.block(c)
+		}
+	}
+}
+
+func (l *odtLayout) styleName(name string) string {
+	if parent, ok := l.parents[name]; ok {
+		return parent
+	}
+	return name
+}
+
+func (l *odtLayout) list(node *xmlNode, depth int) {
+	for _, item := range node.children {
+		if item.name != "list-item" && item.name != "list-header" {
+			continue
+		}
+		for _, c := range item.children {
+			switch c.name {
+			case "p", "h":
+				l.listItem(l.inline(c), depth)
+			case "list":
+				l.list(c, depth+1)
+			}
+		}
+	}
+}
+
+func (l *odtLayout) table(node *xmlNode) {
+	for _, c := range node.children {
+		switch c.name {
+		case "table-header-rows", "table-rows", "table-row-group":
+			l.table(c)
+		case "table-row":
+			cells := make([]string, 0)
+			for _, cell := range c.children {
+				if cell.name != "table-cell" {
+					continue
+				}
+				paragraphs := make([]string, 0)
+				for _, p := range cell.children {
+					if p.name == "p" || p.name == "h" {
+						paragraphs = append(paragraphs, collapseSpaces(l.inline(p)))
+					}
+				}
+				cells = append(cells, strings.Join(paragraphs, " "))
+			}
+			l.tableRow(cells)
+		}
+	}
+	l.inList = false
+}
+
+// inline returns the text of a paragraph, expanding spaces and tabs and replacing notes
+// with their marker.
+func (l *odtLayout) inline(node *xmlNode) string {
+	switch node.name {
+	case "":
+		return strings.ReplaceAll(node.text, "\n", " ")
+	case "s":
+		n, err := strconv.Atoi(node.attr("c"))
+		if err != nil || n < 1 {
+			n = 1
+		}
+		return strings.Repeat(" ", n)
+	case "tab":
+		return " "
+	case "line-break":
+		return "\n"
+	case "note":
+		paragraphs := make([]string, 0)
+		if body := node.child("note-body"); body != nil {
+			for _, p := range body.children {
+				if p.name == "p" {
+					paragraphs = append(paragraphs, l.inline(p))
+				}
+			}
+		}
+		return l.note(strings.Join(paragraphs, " "))
+	case "annotation", "note-citation", "soft-page-break", "tracked-changes":
+		return ""
+	}
+	var sb strings.Builder
+	for _, c := range node.children {
+		sb.WriteString(l.inline(c))
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package file
+
+import (
+	"reflect"
+	"testing"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+)
+
+func TestReadDOCX(t *testing.T) {
+	docx := buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:body>
+    <w:p><w:pPr><w:pStyle w:val="Titre"/></w:pPr><w:r><w:t>Manuscript</w:t></w:r></w:p>
+    <w:p><w:pPr><w:pStyle w:val="Titre1"/></w:pPr><w:r><w:t>First </w:t></w:r><w:r><w:t>chapter</w:t></w:r></w:p>
+    <w:p><w:r><w:t xml:space="preserve">It was a dark </w:t></w:r><w:r><w:t>night</w:t></w:r><w:r><w:footnoteReference w:id="2"/></w:r><w:r><w:delText>deleted</w:delText></w:r><w:r><w:t>.</w:t><w:br/><w:t>New line.</w:t></w:r></w:p>
+    <w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>one</w:t></w:r></w:p>
+    <w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>two</w:t></w:r></w:p>
+    <w:p><w:pPr><w:pStyle w:val="Quote"/></w:pPr><w:r><w:t>Quoted.</w:t></w:r></w:p>
+    <w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+    <w:p><w:pPr><w:outlineLvl w:val="1"/></w:pPr><w:r><w:t>Second chapter</w:t></w:r></w:p>
+  </w:body>
+</w:document>`,
+		"word/styles.xml": `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:style w:type="paragraph" w:styleId="Titre"><w:name w:val="Title"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Titre1"><w:name w:val="heading 1"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/></w:style>
+</w:styles>`,
+		"word/footnotes.xml": `<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>
+  <w:footnote w:id="2"><w:p><w:r><w:footnoteRef/></w:r><w:r><w:t xml:space="preserve"> A note.</w:t></w:r></w:p></w:footnote>
+</w:footnotes>`,
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="cp" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <dc:title>Manuscript</dc:title><dc:creator>Ana</dc:creator><dc:language>es-MX</dc:language>
+</cp:coreProperties>`,
+	})
+
+	doc, err := ReadDOCX(docx, docx.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLines := []string{
+		"Manuscript",
+		"",
+		"First chapter",
+		"",
+		"It was a dark night[1].",
+		"New line.",
+		"",
+		"• one",
+		"  • two",
+		"",
+		"│ Quoted.",
+		"",
+		"a | b",
+		"",
+		"Second chapter",
+		"",
+		"Notes",
+		"",
+		"[1] A note.",
+	}
+	if !reflect.DeepEqual(doc.Lines, wantLines) {
+		t.Errorf("Lines = %q, want %q", doc.Lines, wantLines)
+	}
+	wantChapters := []model.Chapter{{Title: "First chapter", Line: 2}, {Title: "Second chapter", Line: 14}, {Title: "Notes", Line: 16}}
+	if !reflect.DeepEqual(doc.Chapters, wantChapters) {
+		t.Errorf("Chapters = %v, want %v", doc.Chapters, wantChapters)
+	}
+	for _, line := range []int{0, 2, 14, 16} {
+		if doc.Styles[line] != markup.HeadingStyle {
+			t.Errorf("line %d style = %q, want heading", line, doc.Styles[line])
+		}
+	}
+	if want := (model.BookMetadata{Title: "Manuscript", Author: "Ana", Language: "es-MX"}); doc.Metadata != want {
+		t.Errorf("Metadata = %v, want %v", doc.Metadata, want)
+	}
+}
+
+func TestReadODT(t *testing.T) {
+	odt := buildZip(t, map[string]string{
+		"mimetype": "application/vnd.oasis.opendocument.text",
+		"content.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0">
+  <office:automatic-styles>
+    <style:style style:name="P1" style:family="paragraph" style:parent-style-name="Title"/>
+  </office:automatic-styles>
+  <office:body>
+    <office:text>
+      <text:p text:style-name="P1">Informe</text:p>
+      <text:h text:outline-level="1">Capítulo uno</text:h>
+      <text:p text:style-name="Text_20_body">Hola<text:s text:c="2"/>mundo<text:note text:note-class="footnote"><text:note-citation>1</text:note-citation><text:note-body><text:p>Nota al pie.</text:p></text:note-body></text:note>.<text:line-break/>Otra línea.</text:p>
+      <text:list><text:list-item><text:p>uno</text:p><text:list><text:list-item><text:p>dos</text:p></text:list-item></text:list></text:list-item></text:list>
+      <text:section><text:p text:style-name="Quotations">Cita.</text:p></text:section>
+      <table:table><table:table-row><table:table-cell><text:p>x</text:p></table:table-cell><table:table-cell><text:p>y</text:p></table:table-cell></table:table-row></table:table>
+    </office:text>
+  </office:body>
+</office:document-content>`,
+		"meta.xml": `<office:document-meta xmlns:office="o" xmlns:meta="m" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <office:meta><dc:title>Informe</dc:title><meta:initial-creator>Luis</meta:initial-creator><dc:language>es</dc:language></office:meta>
+</office:document-meta>`,
+	})
+
+	doc, err := ReadODT(odt, odt.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLines := []string{
+		"Informe",
+		"",
+		"Capítulo uno",
+		"",
+		"Hola mundo[1].",
+		"Otra línea.",
+		"",
+		"• uno",
+		"  • dos",
+		"",
+		"│ Cita.",
+		"",
+		"x | y",
+		"",
+		"Notes",
+		"",
+		"[1] Nota al pie.",
+	}
+	if !reflect.DeepEqual(doc.Lines, wantLines) {
+		t.Errorf("Lines = %q, want %q", doc.Lines, wantLines)
+	}
+	wantChapters := []model.Chapter{{Title: "Capítulo uno", Line: 2}, {Title: "Notes", Line: 14}}
+	if !reflect.DeepEqual(doc.Chapters, wantChapters) {
+		t.Errorf("Chapters = %v, want %v", doc.Chapters, wantChapters)
+	}
+	if doc.Styles[0] != markup.HeadingStyle || doc.Styles[2] != markup.HeadingStyle {
+		t.Errorf("Styles = %v, want headings on lines 0 and 2", doc.Styles)
+	}
+	if want := (model.BookMetadata{Title: "Informe", Author: "Luis", Language: "es"}); doc.Metadata != want {
+		t.Errorf("Metadata = %v, want %v", doc.Metadata, want)
+	}
+}
@@ -0,0 +1,501 @@
+package file
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// The PDF object model, as much of it as we need to get the text out.
+type (
+	pdfName    string
+	pdfString  string
+	pdfKeyword string
+	pdfArray   []interface{}
+	pdfDict    map[pdfName]interface{}
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		data []byte
+	}
+)
+
+// pdfLexer splits PDF syntax (objects and content streams) into tokens: float64 numbers,
+// pdfName, pdfString and pdfKeyword, which also carries the delimiters "[", "]", "<<" and ">>".
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (lx *pdfLexer) skipSpace() {
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		switch {
+		case isPDFWhitespace(c):
+			lx.pos++
+		case c == '%':
+			for lx.pos < len(lx.data) && lx.data[lx.pos] != '\n' && lx.data[lx.pos] != '\r' {
+				lx.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (lx *pdfLexer) next() (interface{}, error) {
+	lx.skipSpace()
+	if lx.pos >= len(lx.data) {
+		return nil, io.EOF
+	}
+
+	c := lx.data[lx.pos]
+	switch {
+	case c == '(':
+		return lx.literalString(), nil
+	case c == '<' && lx.pos+1 < len(lx.data) && lx.data[lx.pos+1] == '<':
+		lx.pos += 2
+		return pdfKeyword("<<"), nil
+	case c == '>' && lx.pos+1 < len(lx.data) && lx.data[lx.pos+1] == '>':
+		lx.pos += 2
+		return pdfKeyword(">>"), nil
+	case c == '<':
+		return lx.hexString(), nil
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		lx.pos++
+		return pdfKeyword(string(c)), nil
+	case c == '/':
+		lx.pos++
+		return pdfName(lx.name()), nil
+	}
+
+	start := lx.pos
+	for lx.pos < len(lx.data) && !isPDFWhitespace(lx.data[lx.pos]) && !isPDFDelimiter(lx.data[lx.pos]) {
+		lx.pos++
+	}
+	if lx.pos == start {
+		// A stray delimiter such as ")" or ">", skip it.
+		lx.pos++
+		return pdfKeyword(string(c)), nil
+	}
+	word := string(lx.data[start:lx.pos])
+	if n, err := strconv.ParseFloat(word, 64); err == nil && (word[0] < 'A' || word[0] > 'z') {
+		return n, nil
+	}
+	return pdfKeyword(word), nil
+}
+
+func (lx *pdfLexer) name() string {
+	var sb bytes.Buffer
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		if isPDFWhitespace(c) || isPDFDelimiter(c) {
+			break
+		}
+		if c == '#' && lx.pos+2 < len(lx.data) {
+			if b, err := hex.DecodeString(string(lx.data[lx.pos+1 : lx.pos+3])); err == nil {
+				sb.WriteByte(b[0])
+				lx.pos += 3
+				continue
+			}
+		}
+		sb.WriteByte(c)
+		lx.pos++
+	}
+	return sb.String()
+}
+
+func (lx *pdfLexer) literalString() pdfString {
+	lx.pos++ // (
+	var sb bytes.Buffer
+	depth := 1
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		lx.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(sb.String())
+			}
+		case '\\':
+			if lx.pos >= len(lx.data) {
+				continue
+			}
+			e := lx.data[lx.pos]
+			lx.pos++
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case '\r':
+				if lx.pos < len(lx.data) && lx.data[lx.pos] == '\n' {
+					lx.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					n := int(e - '0')
+					for i := 0; i < 2 && lx.pos < len(lx.data) && lx.data[lx.pos] >= '0' && lx.data[lx.pos] <= '7'; i++ {
+						n = n*8 + int(lx.data[lx.pos]-'0')
+						lx.pos++
+					}
+					sb.WriteByte(byte(n))
+				} else {
+					sb.WriteByte(e)
+				}
+			}
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return pdfString(sb.String())
+}
+
+func (lx *pdfLexer) hexString() pdfString {
+	lx.pos++ // <
+	digits := make([]byte, 0, 32)
+	for lx.pos < len(lx.data) && lx.data[lx.pos] != '>' {
+		if c := lx.data[lx.pos]; !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+		lx.pos++
+	}
+	lx.pos++ // >
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	decoded, _ := hex.DecodeString(string(digits))
+	return pdfString(decoded)
+}
+
+// object parses the next complete object, resolving "n g R" into references.
+func (lx *pdfLexer) object() (interface{}, error) {
+	token, err := lx.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case float64:
+		save := lx.pos
+		if gen, err := lx.next(); err == nil {
+			if g, ok := gen.(float64); ok {
+				if r, err := lx.next(); err == nil && r == pdfKeyword("R") {
+					return pdfRef{num: int(t), gen: int(g)}, nil
+				}
+			}
+		}
+		lx.pos = save
+		return t, nil
+	case pdfKeyword:
+		switch t {
+		case "[":
+			array := pdfArray{}
+			for {
+				lx.skipSpace()
+				if lx.pos < len(lx.data) && lx.data[lx.pos] == ']' {
+					lx.pos++
+					return array, nil
+				}
+				item, err := lx.object()
+				if err != nil {
+					return array, err
+				}
+				array = append(array, item)
+			}
+		case "<<":
+			dict := pdfDict{}
+			for {
+				key, err := lx.object()
+				if err != nil {
+					return dict, err
+				}
+				if key == pdfKeyword(">>") {
+					return dict, nil
+				}
+				name, ok := key.(pdfName)
+				if !ok {
+					continue
+				}
+				value, err := lx.object()
+				if err != nil {
+					return dict, err
+				}
+				dict[name] = value
+			}
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+	return token, nil
+}
+
+// pdfDocument holds every object of a PDF file by object number.
+type pdfDocument struct {
+	objects map[int]interface{}
+	// trailers are the classic trailer dictionaries, the one of the last update last.
+	trailers []pdfDict
+}
+
+var (
+	pdfObjectRe  = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfTrailerRe = regexp.MustCompile(`\btrailer\s*<<`)
+)
+
+// parsePDF collects the objects of a PDF by scanning for "n g obj" headers instead of
+// trusting the cross-reference table, which is often broken. Later definitions win, as they
+// do with incremental updates.
+func parsePDF(data []byte) (*pdfDocument, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF file")
+	}
+
+	doc := &pdfDocument{objects: map[int]interface{}{}}
+	skipUntil := 0
+	for _, m := range pdfObjectRe.FindAllSubmatchIndex(data, -1) {
+		if m[0] < skipUntil {
+			continue
+		}
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		lx := &pdfLexer{data: data, pos: m[1]}
+		obj, err := lx.object()
+		if err != nil {
+			continue
+		}
+		if dict, ok := obj.(pdfDict); ok {
+			save := lx.pos
+			if token, err := lx.next(); err == nil && token == pdfKeyword("stream") {
+				stream, end := readStreamData(data, lx.pos, dict)
+				obj = stream
+				skipUntil = end
+			} else {
+				lx.pos = save
+			}
+		}
+		doc.objects[num] = obj
+	}
+
+	for _, m := range pdfTrailerRe.FindAllIndex(data, -1) {
+		lx := &pdfLexer{data: data, pos: m[0] + len("trailer")}
+		if obj, err := lx.object(); err == nil {
+			if dict, ok := obj.(pdfDict); ok {
+				doc.trailers = append(doc.trailers, dict)
+			}
+		}
+	}
+
+	if _, encrypted := doc.trailerValue("Encrypt"); encrypted {
+		return nil, fmt.Errorf("encrypted PDF files are not supported")
+	}
+
+	doc.expandObjectStreams()
+	return doc, nil
+}
+
+func readStreamData(data []byte, pos int, dict pdfDict) (pdfStream, int) {
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+
+	if length, ok := dict["Length"].(float64); ok && length >= 0 {
+		end := pos + int(length)
+		if end >= pos && end <= len(data) && bytes.HasPrefix(bytes.TrimLeft(data[end:], "\r\n \t"), []byte("endstream")) {
+			return pdfStream{dict: dict, data: data[pos:end]}, end
+		}
+	}
+	end := bytes.Index(data[pos:], []byte("endstream"))
+	if end < 0 {
+		return pdfStream{dict: dict, data: data[pos:]}, len(data)
+	}
+	streamData := bytes.TrimRight(data[pos:pos+end], "\r\n")
+	return pdfStream{dict: dict, data: streamData}, pos + end
+}
+
+// trailerValue looks a key up in the trailer dictionaries, the latest first, or in a
+// cross-reference stream.
+func (d *pdfDocument) trailerValue(key pdfName) (interface{}, bool) {
+	for i := len(d.trailers) - 1; i >= 0; i-- {
+		if v, ok := d.trailers[i][key]; ok {
+			return v, true
+		}
+	}
+	for _, obj := range d.objects {
+		if stream, ok := obj.(pdfStream); ok && stream.dict["Type"] == pdfName("XRef") {
+			if v, ok := stream.dict[key]; ok {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// expandObjectStreams adds the objects compressed inside /ObjStm streams.
+func (d *pdfDocument) expandObjectStreams() {
+	for _, obj := range d.objects {
+		stream, ok := obj.(pdfStream)
+		if !ok || stream.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := stream.dict["N"].(float64)
+		first, _ := stream.dict["First"].(float64)
+		header := &pdfLexer{data: data}
+		for i := 0; i < int(n); i++ {
+			num, err1 := header.next()
+			offset, err2 := header.next()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			objNum, ok1 := num.(float64)
+			objOffset, ok2 := offset.(float64)
+			if !ok1 || !ok2 {
+				break
+			}
+			if _, defined := d.objects[int(objNum)]; defined {
+				continue
+			}
+			start := int(first) + int(objOffset)
+			if first < 0 || objOffset < 0 || start < 0 || start >= len(data) {
+				continue
+			}
+			lx := &pdfLexer{data: data, pos: start}
+			if value, err := lx.object(); err == nil {
+				d.objects[int(objNum)] = value
+			}
+		}
+	}
+}
+
+func (d *pdfDocument) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.num]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v interface{}) pdfDict {
+	switch t := d.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case pdfStream:
+		return t.dict
+	}
+	return nil
+}
+
+func (d *pdfDocument) array(v interface{}) pdfArray {
+	if array, ok := d.resolve(v).(pdfArray); ok {
+		return array
+	}
+	return nil
+}
+
+func (d *pdfDocument) number(v interface{}, fallback float64) float64 {
+	if n, ok := d.resolve(v).(float64); ok {
+		return n
+	}
+	return fallback
+}
+
+// decodeStream applies the stream filters we know about.
+func (d *pdfDocument) decodeStream(stream pdfStream) ([]byte, error) {
+	filters := make([]pdfName, 0)
+	switch f := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = append(filters, f)
+	case pdfArray:
+		for _, item := range f {
+			if name, ok := d.resolve(item).(pdfName); ok {
+				filters = append(filters, name)
+			}
+		}
+	}
+
+	data := stream.data
+	for _, filter := range filters {
+		var err error
+		switch filter {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data)
+		case "ASCIIHexDecode", "AHx":
+			data = []byte(string((&pdfLexer{data: append(append([]byte("<"), data...), '>')}).hexString()))
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			err = fmt.Errorf("unsupported stream filter %s", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate decompresses zlib (or raw deflate) data, keeping whatever could be read from a
+// truncated stream.
+func inflate(data []byte) ([]byte, error) {
+	var r io.Reader
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err == nil {
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	out, err := io.ReadAll(r)
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("failed to inflate stream: %w", err)
+	}
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data))
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ascii85 stream: %w", err)
+	}
+	return out[:n], nil
+}
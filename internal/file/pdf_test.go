@@ -0,0 +1,156 @@
+package file
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildPDF writes a PDF with the given objects (numbered from 1) and a trailer. Stream
+// objects are given as dictionary + "\nstream\n" + data, Length is filled in for them.
+func buildPDF(objects []string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, obj := range objects {
+		if parts := strings.SplitN(obj, "\nstream\n", 2); len(parts) == 2 {
+			obj = fmt.Sprintf("%s /Length %d >>\nstream\n%s\nendstream", strings.TrimSuffix(parts[0], ">>"), len(parts[1]), parts[1])
+		}
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func deflate(s string) string {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	_, _ = w.Write([]byte(s))
+	_ = w.Close()
+	return b.String()
+}
+
+func TestReadPDF(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0001> <0048>
+<0002> <00F3>
+endbfchar
+1 beginbfrange
+<0010> <0012> <0061>
+endbfrange
+endcmap`
+	page1 := `BT /F1 12 Tf 72 720 Td (Call me Ishmael.) Tj 0 -14 Td [(Some) -250 (years) -250 (ago)] TJ
+0 -40 Td (\(Second paragraph\)) Tj ET
+BT /F1 12 Tf 300 720 Td ( never mind) Tj ET`
+	page2 := `BT /F2 10 Tf 72 700 Td <00010002001000110012> Tj ET`
+
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 7 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [8 0 R] >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Foo /Encoding /Identity-H /DescendantFonts [10 0 R] /ToUnicode 9 0 R >>",
+		"<< /Filter /FlateDecode >>\nstream\n" + deflate(page1),
+		"<< >>\nstream\n" + page2,
+		"<< >>\nstream\n" + cmap,
+		"<< /Type /Font /Subtype /CIDFontType2 /DW 500 /W [1 [600 500]] >>",
+	})
+
+	doc, err := ReadPDF(data)
+	if err != nil {
+		t.Fatalf("ReadPDF() error = %v", err)
+	}
+	want := []string{
+		"Call me Ishmael. never mind",
+		"Some years ago",
+		"",
+		"(Second paragraph)",
+		"── 2 ──",
+		"Hóabc",
+	}
+	if !reflect.DeepEqual(doc.Lines, want) {
+		t.Errorf("Lines = %q, want %q", doc.Lines, want)
+	}
+	if !reflect.DeepEqual(doc.PageBreaks, []int{0, 4}) {
+		t.Errorf("PageBreaks = %v, want [0 4]", doc.PageBreaks)
+	}
+}
+
+func TestReadPDFObjectStream(t *testing.T) {
+	page := "<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 7 0 R >> >> >>"
+	font := "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+	header := fmt.Sprintf("6 0 7 %d ", len(page)+1)
+
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [6 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /ObjStm /N 2 /First %d /Filter /FlateDecode >>\nstream\n%s", len(header), deflate(header+page+" "+font)),
+		"<< >>\nstream\nBT /F1 9 Tf 10 10 Td (Compressed) Tj ET",
+	})
+
+	doc, err := ReadPDF(data)
+	if err != nil {
+		t.Fatalf("ReadPDF() error = %v", err)
+	}
+	if !reflect.DeepEqual(doc.Lines, []string{"Compressed"}) {
+		t.Errorf("Lines = %q", doc.Lines)
+	}
+}
+
+func TestReadPDFWithoutText(t *testing.T) {
+	data := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< >>\nstream\n0 0 100 100 re f",
+	})
+	if _, err := ReadPDF(data); err == nil {
+		t.Error("ReadPDF() expected an error for a PDF without text")
+	}
+}
+
+func TestReadPDFEncrypted(t *testing.T) {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< >>\nstream\n\x8f\x12\xa3 garbled \x01",
+		"<< /Filter /Standard /V 1 /R 2 /O (owner) /U (user) /P -4 >>",
+	}
+	data := buildPDF(objects)
+	// A classic trailer pointing at the encryption dictionary.
+	data = bytes.Replace(data, []byte("trailer\n<< /Root 1 0 R >>"), []byte("trailer\n<< /Root 1 0 R /Encrypt 5 0 R /ID [<01> <01>] >>"), 1)
+
+	_, err := ReadPDF(data)
+	if err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("got=[%v], want=[encrypted PDF files are not supported]", err)
+	}
+}
+
+func TestReadPDFMalformed(t *testing.T) {
+	page := "<< /Type /Page >>"
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "negative length", data: []byte("%PDF-1.7\n1 0 obj\n<< /Length -500 >>\nstream\nabc\nendstream\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")},
+		{name: "object stream offset", data: buildPDF([]string{
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [6 0 R] /Count 1 >>",
+			fmt.Sprintf("<< /Type /ObjStm /N 1 /First -100 >>\nstream\n6 0 %s", page),
+		})},
+	}
+	for _, tc := range tests {
+		// Malformed files are reported, they must not panic.
+		if _, err := ReadPDF(tc.data); err == nil {
+			t.Errorf("%s: ReadPDF() expected an error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,721 @@
+package file
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"textreader/internal/markup"
+	"textreader/internal/model"
+	"textreader/internal/wrap"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// pdfFont knows how to split a shown string into character codes, what text each code
+// stands for and how far it advances the pen.
+type pdfFont struct {
+	codeLength   int
+	toUnicode    map[int]string
+	fallback     func(code int) string
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+type pdfGlyph struct {
+	code int
+	text string
+}
+
+func (f *pdfFont) decode(s pdfString) []pdfGlyph {
+	glyphs := make([]pdfGlyph, 0, len(s))
+	for i := 0; i+f.codeLength <= len(s); i += f.codeLength {
+		code := 0
+		for j := 0; j < f.codeLength; j++ {
+			code = code<<8 | int(s[i+j])
+		}
+		t, ok := f.toUnicode[code]
+		if !ok && f.fallback != nil {
+			t = f.fallback(code)
+		}
+		glyphs = append(glyphs, pdfGlyph{code: code, text: t})
+	}
+	return glyphs
+}
+
+func (f *pdfFont) width(code int) float64 {
+	if w, ok := f.widths[code]; ok {
+		return w
+	}
+	return f.defaultWidth
+}
+
+// pdfGlyphNames covers the glyph names of /Differences arrays that are not a single letter
+// or a uniXXXX name.
+var pdfGlyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+	"percent": "%", "ampersand": "&", "quotesingle": "'", "quoteright": "’", "quoteleft": "‘",
+	"parenleft": "(", "parenright": ")", "asterisk": "*", "plus": "+", "comma": ",",
+	"hyphen": "-", "period": ".", "slash": "/", "colon": ":", "semicolon": ";", "less": "<",
+	"equal": "=", "greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "underscore": "_", "braceleft": "{", "bar": "|",
+	"braceright": "}", "quotedblleft": "“", "quotedblright": "”", "endash": "–",
+	"emdash": "—", "bullet": "•", "ellipsis": "…", "fi": "fi", "fl": "fl", "ff": "ff",
+	"ffi": "ffi", "ffl": "ffl", "zero": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9", "exclamdown": "¡",
+	"questiondown": "¿", "guillemotleft": "«", "guillemotright": "»", "aacute": "á",
+	"eacute": "é", "iacute": "í", "oacute": "ó", "uacute": "ú", "ntilde": "ñ", "Aacute": "Á",
+	"Eacute": "É", "Iacute": "Í", "Oacute": "Ó", "Uacute": "Ú", "Ntilde": "Ñ", "udieresis": "ü",
+	"Udieresis": "Ü", "ccedilla": "ç", "agrave": "à", "egrave": "è", "germandbls": "ß",
+}
+
+func glyphNameText(name string) string {
+	if t, ok := pdfGlyphNames[name]; ok {
+		return t
+	}
+	if len(name) == 1 {
+		return name
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if n, err := strconv.ParseUint(name[3:], 16, 32); err == nil {
+			return string(rune(n))
+		}
+	}
+	return ""
+}
+
+// loadFont reads a font dictionary. Text comes from the ToUnicode CMap when there is one,
+// otherwise from the simple font encoding (WinAnsi and /Differences).
+func (d *pdfDocument) loadFont(v interface{}) *pdfFont {
+	dict := d.dict(v)
+	font := &pdfFont{codeLength: 1, toUnicode: map[int]string{}, widths: map[int]float64{}, defaultWidth: 500}
+	if dict == nil {
+		font.fallback = func(code int) string { return string(rune(code)) }
+		return font
+	}
+
+	if dict["Subtype"] == pdfName("Type0") {
+		font.codeLength = 2
+		font.defaultWidth = 1000
+		if descendants := d.array(dict["DescendantFonts"]); len(descendants) > 0 {
+			d.loadCIDWidths(d.dict(descendants[0]), font)
+		}
+	} else {
+		d.loadSimpleFont(dict, font)
+	}
+
+	if stream, ok := d.resolve(dict["ToUnicode"]).(pdfStream); ok {
+		if data, err := d.decodeStream(stream); err == nil {
+			parseToUnicode(data, font.toUnicode)
+		}
+	}
+	return font
+}
+
+func (d *pdfDocument) loadSimpleFont(dict pdfDict, font *pdfFont) {
+	first := int(d.number(dict["FirstChar"], 0))
+	for i, w := range d.array(dict["Widths"]) {
+		font.widths[first+i] = d.number(w, 0)
+	}
+	if descriptor := d.dict(dict["FontDescriptor"]); descriptor != nil {
+		font.defaultWidth = d.number(descriptor["MissingWidth"], font.defaultWidth)
+	}
+
+	differences := map[int]string{}
+	encoding := dict["Encoding"]
+	if encodingDict := d.dict(encoding); encodingDict != nil {
+		encoding = encodingDict["BaseEncoding"]
+		code := 0
+		for _, item := range d.array(encodingDict["Differences"]) {
+			switch t := d.resolve(item).(type) {
+			case float64:
+				code = int(t)
+			case pdfName:
+				differences[code] = glyphNameText(string(t))
+				code++
+			}
+		}
+	}
+	winAnsi := d.resolve(encoding) == pdfName("WinAnsiEncoding")
+	font.fallback = func(code int) string {
+		if t, ok := differences[code]; ok {
+			return t
+		}
+		if winAnsi {
+			return string(cp1252Rune(byte(code)))
+		}
+		return string(rune(code))
+	}
+}
+
+// loadCIDWidths reads the /W array of a CID font: "c [w1 w2 ...]" or "cFirst cLast w".
+func (d *pdfDocument) loadCIDWidths(dict pdfDict, font *pdfFont) {
+	if dict == nil {
+		return
+	}
+	font.defaultWidth = d.number(dict["DW"], 1000)
+	w := d.array(dict["W"])
+	for i := 0; i+1 < len(w); {
+		first := int(d.number(w[i], 0))
+		if widths, ok := d.resolve(w[i+1]).(pdfArray); ok {
+			for j, width := range widths {
+				font.widths[first+j] = d.number(width, 0)
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			break
+		}
+		last, width := int(d.number(w[i+1], 0)), d.number(w[i+2], 0)
+		for c := first; c <= last && c-first < 65536; c++ {
+			font.widths[c] = width
+		}
+		i += 3
+	}
+}
+
+// parseToUnicode reads the bfchar and bfrange mappings of a ToUnicode CMap.
+func parseToUnicode(data []byte, mapping map[int]string) {
+	lx := &pdfLexer{data: data}
+	readUntil := func(end pdfKeyword, n int, handle func([]interface{})) {
+		for {
+			items := make([]interface{}, 0, n)
+			for len(items) < n {
+				item, err := lx.object()
+				if err != nil || item == end {
+					return
+				}
+				items = append(items, item)
+			}
+			handle(items)
+		}
+	}
+
+	for {
+		token, err := lx.object()
+		if err != nil {
+			return
+		}
+		switch token {
+		case pdfKeyword("beginbfchar"):
+			readUntil("endbfchar", 2, func(items []interface{}) {
+				src, ok1 := items[0].(pdfString)
+				dst, ok2 := items[1].(pdfString)
+				if ok1 && ok2 {
+					mapping[cmapCode(src)] = utf16BEText(dst)
+				}
+			})
+		case pdfKeyword("beginbfrange"):
+			readUntil("endbfrange", 3, func(items []interface{}) {
+				lo, ok1 := items[0].(pdfString)
+				hi, ok2 := items[1].(pdfString)
+				if !ok1 || !ok2 {
+					return
+				}
+				first, last := cmapCode(lo), cmapCode(hi)
+				if last-first > 65535 {
+					return
+				}
+				switch dst := items[2].(type) {
+				case pdfString:
+					base := []rune(utf16BEText(dst))
+					if len(base) == 0 {
+						return
+					}
+					for c := first; c <= last; c++ {
+						runes := append([]rune{}, base...)
+						runes[len(runes)-1] += rune(c - first)
+						mapping[c] = string(runes)
+					}
+				case pdfArray:
+					for i, item := range dst {
+						if s, ok := item.(pdfString); ok && first+i <= last {
+							mapping[first+i] = utf16BEText(s)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func cmapCode(s pdfString) int {
+	code := 0
+	for i := 0; i < len(s); i++ {
+		code = code<<8 | int(s[i])
+	}
+	return code
+}
+
+func utf16BEText(s pdfString) string {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfMatrix is a PDF transformation matrix [a b c d e f].
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+func (m pdfMatrix) multiply(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func translation(tx, ty float64) pdfMatrix {
+	return pdfMatrix{1, 0, 0, 1, tx, ty}
+}
+
+type pdfGraphicsState struct {
+	ctm         pdfMatrix
+	font        *pdfFont
+	size        float64
+	charSpacing float64
+	wordSpacing float64
+	scale       float64
+	leading     float64
+}
+
+// pdfRun is a piece of text drawn in one go, in device space.
+type pdfRun struct {
+	x, y, endX, size float64
+	text             string
+}
+
+type pdfInterpreter struct {
+	doc   *pdfDocument
+	fonts map[pdfRef]*pdfFont
+	runs  []pdfRun
+	depth int
+}
+
+func (in *pdfInterpreter) font(resources pdfDict, name pdfName) *pdfFont {
+	ref := in.doc.dict(resources["Font"])[name]
+	if r, ok := ref.(pdfRef); ok {
+		if f, ok := in.fonts[r]; ok {
+			return f
+		}
+		f := in.doc.loadFont(r)
+		in.fonts[r] = f
+		return f
+	}
+	return in.doc.loadFont(ref)
+}
+
+// run interprets a content stream, collecting the text it shows.
+func (in *pdfInterpreter) run(content []byte, resources pdfDict, gs pdfGraphicsState) {
+	in.depth++
+	defer func() { in.depth-- }()
+	if in.depth > 8 {
+		return
+	}
+
+	stack := make([]pdfGraphicsState, 0)
+	tm, tlm := pdfIdentity, pdfIdentity
+	operands := make([]interface{}, 0)
+	num := func(i int) float64 {
+		if i < len(operands) {
+			if n, ok := operands[i].(float64); ok {
+				return n
+			}
+		}
+		return 0
+	}
+	nextLine := func(tx, ty float64) {
+		tlm = translation(tx, ty).multiply(tlm)
+		tm = tlm
+	}
+	show := func(s pdfString) {
+		if gs.font == nil {
+			gs.font = in.doc.loadFont(nil)
+		}
+		start := tm.multiply(gs.ctm)
+		var sb strings.Builder
+		for _, g := range gs.font.decode(s) {
+			sb.WriteString(g.text)
+			advance := gs.font.width(g.code)/1000*gs.size + gs.charSpacing
+			if g.code == ' ' && gs.font.codeLength == 1 {
+				advance += gs.wordSpacing
+			}
+			tm = translation(advance*gs.scale, 0).multiply(tm)
+		}
+		end := tm.multiply(gs.ctm)
+		in.runs = append(in.runs, pdfRun{
+			x:    start[4],
+			y:    start[5],
+			endX: end[4],
+			size: gs.size * math.Hypot(start[2], start[3]),
+			text: sb.String(),
+		})
+	}
+
+	lx := &pdfLexer{data: content}
+	for {
+		token, err := lx.object()
+		if err != nil {
+			return
+		}
+		op, ok := token.(pdfKeyword)
+		if !ok {
+			operands = append(operands, token)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if len(stack) > 0 {
+				gs = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			gs.ctm = pdfMatrix{num(0), num(1), num(2), num(3), num(4), num(5)}.multiply(gs.ctm)
+		case "BT":
+			tm, tlm = pdfIdentity, pdfIdentity
+		case "Tf":
+			if len(operands) == 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					gs.font = in.font(resources, name)
+				}
+			}
+			gs.size = num(1)
+		case "Tc":
+			gs.charSpacing = num(0)
+		case "Tw":
+			gs.wordSpacing = num(0)
+		case "Tz":
+			gs.scale = num(0) / 100
+		case "TL":
+			gs.leading = num(0)
+		case "Td":
+			nextLine(num(0), num(1))
+		case "TD":
+			gs.leading = -num(1)
+			nextLine(num(0), num(1))
+		case "Tm":
+			tlm = pdfMatrix{num(0), num(1), num(2), num(3), num(4), num(5)}
+			tm = tlm
+		case "T*":
+			nextLine(0, -gs.leading)
+		case "Tj", "'", "\"":
+			if op == "\"" {
+				gs.wordSpacing, gs.charSpacing = num(0), num(1)
+			}
+			if op != "Tj" {
+				nextLine(0, -gs.leading)
+			}
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				items, _ := operands[0].(pdfArray)
+				for _, item := range items {
+					switch t := item.(type) {
+					case pdfString:
+						show(t)
+					case float64:
+						tm = translation(-t/1000*gs.size*gs.scale, 0).multiply(tm)
+					}
+				}
+			}
+		case "Do":
+			if len(operands) > 0 {
+				if name, ok := operands[0].(pdfName); ok {
+					in.form(in.doc.dict(resources["XObject"])[name], resources, gs)
+				}
+			}
+		case "BI":
+			skipInlineImage(lx)
+		}
+		operands = operands[:0]
+	}
+}
+
+// form runs the content of a form XObject, which may hold text of its own.
+func (in *pdfInterpreter) form(v interface{}, resources pdfDict, gs pdfGraphicsState) {
+	stream, ok := in.doc.resolve(v).(pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := in.doc.decodeStream(stream)
+	if err != nil {
+		return
+	}
+	if formResources := in.doc.dict(stream.dict["Resources"]); formResources != nil {
+		resources = formResources
+	}
+	if m := in.doc.array(stream.dict["Matrix"]); len(m) == 6 {
+		var matrix pdfMatrix
+		for i := range matrix {
+			matrix[i] = in.doc.number(m[i], 0)
+		}
+		gs.ctm = matrix.multiply(gs.ctm)
+	}
+	in.run(data, resources, gs)
+}
+
+// skipInlineImage moves past the binary data between "ID" and "EI".
+func skipInlineImage(lx *pdfLexer) {
+	for {
+		token, err := lx.next()
+		if err != nil {
+			return
+		}
+		if token == pdfKeyword("ID") {
+			break
+		}
+	}
+	for lx.pos+2 <= len(lx.data) {
+		if lx.data[lx.pos] == 'E' && lx.data[lx.pos+1] == 'I' && isPDFWhitespace(lx.data[lx.pos-1]) &&
+			(lx.pos+2 == len(lx.data) || isPDFWhitespace(lx.data[lx.pos+2])) {
+			lx.pos += 2
+			return
+		}
+		lx.pos++
+	}
+	lx.pos = len(lx.data)
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree from the document catalog, in reading order.
+func (d *pdfDocument) pages() []pdfPage {
+	catalog := -1
+	for num, obj := range d.objects {
+		if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Catalog") && (catalog < 0 || num < catalog) {
+			catalog = num
+		}
+	}
+	if catalog < 0 {
+		return nil
+	}
+
+	pages := make([]pdfPage, 0)
+	visited := map[pdfRef]bool{}
+	var walk func(v interface{}, resources pdfDict)
+	walk = func(v interface{}, resources pdfDict) {
+		if ref, ok := v.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		node := d.dict(v)
+		if node == nil {
+			return
+		}
+		if r := d.dict(node["Resources"]); r != nil {
+			resources = r
+		}
+		if node["Type"] == pdfName("Page") || (node["Kids"] == nil && node["Contents"] != nil) {
+			pages = append(pages, pdfPage{dict: node, resources: resources})
+			return
+		}
+		for _, kid := range d.array(node["Kids"]) {
+			walk(kid, resources)
+		}
+	}
+	walk(d.dict(d.objects[catalog])["Pages"], nil)
+	return pages
+}
+
+func (d *pdfDocument) pageContent(page pdfPage) []byte {
+	contents := page.dict["Contents"]
+	streams := d.array(contents)
+	if streams == nil {
+		streams = pdfArray{contents}
+	}
+	data := make([]byte, 0)
+	for _, v := range streams {
+		if stream, ok := d.resolve(v).(pdfStream); ok {
+			if decoded, err := d.decodeStream(stream); err == nil {
+				data = append(append(data, decoded...), '\n')
+			}
+		}
+	}
+	return data
+}
+
+var pdfTextReplacer = strings.NewReplacer(
+	"­", "", "\t", " ", "\r", "", "\n", " ", "\x00", "",
+	"ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi", "ﬄ", "ffl",
+)
+
+// layoutPage sorts the runs of a page into lines, top to bottom and left to right, and puts
+// a blank line where the vertical gap suggests a new paragraph.
+func layoutPage(runs []pdfRun) []string {
+	kept := make([]pdfRun, 0, len(runs))
+	for _, r := range runs {
+		r.text = pdfTextReplacer.Replace(r.text)
+		if strings.TrimSpace(r.text) != "" {
+			kept = append(kept, r)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].y > kept[j].y })
+
+	type pdfLine struct {
+		y    float64
+		runs []pdfRun
+	}
+	lines := make([]pdfLine, 0)
+	for _, r := range kept {
+		if n := len(lines); n > 0 {
+			last := &lines[n-1]
+			if math.Abs(last.y-r.y) <= math.Max(r.size*0.4, 1) {
+				last.runs = append(last.runs, r)
+				continue
+			}
+		}
+		lines = append(lines, pdfLine{y: r.y, runs: []pdfRun{r}})
+	}
+
+	gaps := make([]float64, 0, len(lines))
+	for i := 1; i < len(lines); i++ {
+		gaps = append(gaps, lines[i-1].y-lines[i].y)
+	}
+	sort.Float64s(gaps)
+	lineGap := 0.0
+	if len(gaps) > 0 {
+		lineGap = gaps[(len(gaps)-1)/2]
+	}
+
+	result := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if i > 0 && lineGap > 0 && lines[i-1].y-line.y > lineGap*1.5 {
+			result = append(result, "")
+		}
+		sort.SliceStable(line.runs, func(a, b int) bool { return line.runs[a].x < line.runs[b].x })
+
+		var sb strings.Builder
+		var prev *pdfRun
+		for k := range line.runs {
+			r := &line.runs[k]
+			if prev != nil {
+				// Duplicated runs are a common trick to fake bold text.
+				if r.text == prev.text && math.Abs(r.x-prev.x) < 1 {
+					continue
+				}
+				gap := r.x - prev.endX
+				s := sb.String()
+				if gap > r.size*0.15 && !strings.HasSuffix(s, " ") && !strings.HasPrefix(r.text, " ") {
+					sb.WriteString(" ")
+				}
+			}
+			sb.WriteString(r.text)
+			prev = r
+		}
+		result = append(result, strings.Join(strings.Fields(sb.String()), " "))
+	}
+	return reflow(result)
+}
+
+// reflow rewraps the paragraphs that have lines wider than model.WrapWidth. A line is
+// joined with the next one only when it runs close to the full width, so headings and the
+// last line of a paragraph keep their break. Words hyphenated across lines are joined.
+// Paragraphs of short lines (verse, tables) are left alone.
+func reflow(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	start := 0
+	for end := 0; end <= len(lines); end++ {
+		if end < len(lines) && lines[end] != "" {
+			continue
+		}
+		paragraph := lines[start:end]
+		widest := 0
+		for _, line := range paragraph {
+			if n := utf8.RuneCountInString(line); n > widest {
+				widest = n
+			}
+		}
+		if widest <= model.WrapWidth {
+			result = append(result, paragraph...)
+		} else {
+			joined := ""
+			for i, line := range paragraph {
+				if joined != "" && strings.HasPrefix(line, "•") {
+					result = append(result, wrap.Paragraph(joined, model.WrapWidth)...)
+					joined = ""
+				}
+				switch {
+				case joined == "":
+					joined = line
+				case hyphenated(joined, line):
+					joined = joined[:len(joined)-1] + line
+				default:
+					joined += " " + line
+				}
+				if i == len(paragraph)-1 || utf8.RuneCountInString(line)*4 < widest*3 {
+					result = append(result, wrap.Paragraph(joined, model.WrapWidth)...)
+					joined = ""
+				}
+			}
+		}
+		if end < len(lines) {
+			result = append(result, "")
+		}
+		start = end + 1
+	}
+	return result
+}
+
+func hyphenated(line, next string) bool {
+	if len(line) < 2 || line[len(line)-1] != '-' {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(line[:len(line)-1])
+	after, _ := utf8.DecodeRuneInString(next)
+	return unicode.IsLetter(before) && unicode.IsLower(after)
+}
+
+// ReadPDF extracts the text of a PDF file, page by page. Every page after the first starts
+// with a marker line, and PageBreaks records where each page starts.
+func ReadPDF(data []byte) (Document, error) {
+	pdf, err := parsePDF(data)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to parse pdf: %w", err)
+	}
+	pages := pdf.pages()
+	if len(pages) == 0 {
+		return Document{}, fmt.Errorf("failed to parse pdf: no pages found")
+	}
+
+	doc := Document{Lines: []string{}, Styles: map[int]string{}, PageBreaks: make([]int, 0, len(pages))}
+	in := &pdfInterpreter{doc: pdf, fonts: map[pdfRef]*pdfFont{}}
+	hasText := false
+	for i, page := range pages {
+		doc.PageBreaks = append(doc.PageBreaks, len(doc.Lines))
+		if i > 0 {
+			doc.Styles[len(doc.Lines)] = markup.PageBreakStyle
+			doc.Lines = append(doc.Lines, fmt.Sprintf("── %d ──", i+1))
+		}
+
+		in.runs = in.runs[:0]
+		in.run(pdf.pageContent(page), page.resources, pdfGraphicsState{ctm: pdfIdentity, scale: 1})
+		lines := layoutPage(in.runs)
+		hasText = hasText || len(lines) > 0
+		doc.Lines = append(doc.Lines, lines...)
+	}
+	if !hasText {
+		return Document{}, fmt.Errorf("no text found in pdf, scanned documents are not supported")
+	}
+	return doc, nil
+}
@@ -0,0 +1,103 @@
+package file
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// xmlNode is an element of an XML document, or a piece of text when name is empty. Names
+// are kept without their namespace prefix.
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) childText(name string) string {
+	if c := n.child(name); c != nil {
+		return collapseSpaces(c.plainText())
+	}
+	return ""
+}
+
+func (n *xmlNode) plainText() string {
+	if n.name == "" {
+		return n.text
+	}
+	var sb strings.Builder
+	for _, c := range n.children {
+		sb.WriteString(c.plainText())
+	}
+	return sb.String()
+}
+
+// parseXMLTree reads a whole XML document into memory, leaving out the elements named in
+// skip. The returned node is the document itself, its children are the root elements.
+func parseXMLTree(r io.Reader, skip ...string) (*xmlNode, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charsetReader
+
+	skipped := map[string]bool{}
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	root := &xmlNode{name: "#document"}
+	stack := []*xmlNode{root}
+	skipDepth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		top := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 || skipped[t.Name.Local] {
+				skipDepth++
+				continue
+			}
+			node := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			top.children = append(top.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if skipDepth == 0 {
+				top.children = append(top.children, &xmlNode{text: string(t)})
+			}
+		}
+	}
+	return root, nil
+}
@@ -0,0 +1,43 @@
+package gutenberg
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	startMarkerRe = regexp.MustCompile(`(?i)^\s*\*{3}\s*START OF (THE|THIS) PROJECT GUTENBERG`)
+	endMarkerRe   = regexp.MustCompile(`(?i)^\s*\*{3}\s*END OF (THE|THIS) PROJECT GUTENBERG`)
+	endNoticeRe   = regexp.MustCompile(`(?i)^\s*End of (the )?Project Gutenberg`)
+)
+
+// FindBody returns the range [start, end) of lines holding the book itself, leaving out the
+// Project Gutenberg header and license. found is false when the text has no Gutenberg markers,
+// in which case the whole content is the body.
+func FindBody(lines []string) (start, end int, found bool) {
+	start, end = 0, len(lines)
+
+	for i, line := range lines {
+		if startMarkerRe.MatchString(line) {
+			start, found = i+1, true
+			break
+		}
+	}
+	for i := len(lines) - 1; i >= start; i-- {
+		if endMarkerRe.MatchString(lines[i]) {
+			end, found = i, true
+			break
+		}
+	}
+
+	for start < end && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	for end > start && (strings.TrimSpace(lines[end-1]) == "" || endNoticeRe.MatchString(lines[end-1])) {
+		end--
+	}
+	if !found {
+		return 0, len(lines), false
+	}
+	return start, end, true
+}
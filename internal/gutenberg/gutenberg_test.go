@@ -0,0 +1,44 @@
+package gutenberg
+
+import "testing"
+
+func TestFindBody(t *testing.T) {
+	type test struct {
+		lines      []string
+		start, end int
+		found      bool
+	}
+
+	tests := []test{
+		{
+			lines: []string{
+				"The Project Gutenberg eBook of Don Quijote",
+				"",
+				"*** START OF THE PROJECT GUTENBERG EBOOK DON QUIJOTE ***",
+				"",
+				"En un lugar de la Mancha",
+				"de cuyo nombre no quiero acordarme",
+				"",
+				"End of the Project Gutenberg EBook of Don Quijote",
+				"*** END OF THE PROJECT GUTENBERG EBOOK DON QUIJOTE ***",
+				"Section 1. General Terms of Use",
+			},
+			start: 4, end: 6, found: true,
+		},
+		{
+			lines: []string{"*** START OF THIS PROJECT GUTENBERG EBOOK X ***", "a", "b"},
+			start: 1, end: 3, found: true,
+		},
+		{
+			lines: []string{"", "a", "b", ""},
+			start: 0, end: 4, found: false,
+		},
+	}
+
+	for _, tc := range tests {
+		start, end, found := FindBody(tc.lines)
+		if start != tc.start || end != tc.end || found != tc.found {
+			t.Errorf("got=[%d, %d, %t], want=[%d, %d, %t]", start, end, found, tc.start, tc.end, tc.found)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package keybindings
+
+import (
+	"fmt"
+	"sort"
+	"textreader/internal/chapters"
+	"textreader/internal/file"
+	"textreader/internal/model"
+	"textreader/internal/navigation"
+	"textreader/internal/text"
+	"textreader/internal/ui"
+	"textreader/internal/utils"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// AddBookmarkKeyBindings sets up "b" to bookmark the highlighted line and "B" to open the
+// bookmark manager, where bookmarks can be renamed ("e"), deleted ("x") or jumped to (Enter).
+func AddBookmarkKeyBindings(tuiUI tui.UI, root *ui.KeyBox, fileName string, txtReader, txtArea *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+	// askName opens the name prompt, the table keeps the focus away meanwhile.
+	askName := func(name string, onName func(name string)) {
+		inputCommand.SetFocused(false)
+		state.BookmarksTable.SetFocused(false)
+		ui.AddBookmarkNameWidget(txtReader, state, name, func(name string) {
+			closeBookmarkName(txtReader, inputCommand, state)
+			if state.CurrentNavMode == model.BookmarksNavigationMode {
+				// The table comes after the prompt in the layout, focusing it now would let it
+				// take this same Enter as a jump.
+				go tuiUI.Update(func() { state.BookmarksTable.SetFocused(true) })
+			}
+			onName(name)
+		})
+	}
+	save := func(done string) {
+		if err := file.SaveStatus(fileName, state.From, state.To, state); err != nil {
+			inputCommand.SetText(fmt.Sprintf("Error saving bookmarks: %v", err))
+			return
+		}
+		inputCommand.SetText(done)
+	}
+
+	root.HandleRune(model.AddBookmarkKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return false
+		}
+		line := min(state.From+state.CurrentHighlight, len(state.FileContent)-1)
+		state.EditedBookmark = -1
+		askName(defaultBookmarkName(line, state), func(name string) {
+			if name == "" {
+				name = defaultBookmarkName(line, state)
+			}
+			addBookmark(state, model.Bookmark{Name: name, Line: line})
+			save(fmt.Sprintf("Bookmarked line %d as '%s'", line, name))
+		})
+		return true
+	})
+
+	root.HandleRune(model.ShowBookmarksKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return false
+		}
+		if len(state.Bookmarks) == 0 {
+			inputCommand.SetText("No bookmarks yet, press b to add one")
+			return true
+		}
+		state.CurrentNavMode = model.BookmarksNavigationMode
+		state.Sidebar.SetTitle("Bookmarks")
+		state.Sidebar.SetBorder(true)
+		state.PageIndex = 0
+		prepareTableForBookmarks(state)
+		state.BookmarksTable.SetFocused(true)
+		return true
+	})
+
+	tuiUI.SetKeybinding(model.RenameBookmarkKeyBinding, func() {
+		if state.CurrentNavMode != model.BookmarksNavigationMode {
+			return
+		}
+		i := state.PageIndex + state.BookmarksTable.Selected()
+		if i >= len(state.Bookmarks) {
+			return
+		}
+		state.EditedBookmark = i
+		askName(state.Bookmarks[i].Name, func(name string) {
+			if name != "" {
+				state.Bookmarks[i].Name = name
+				save(fmt.Sprintf("Renamed bookmark to '%s'", name))
+			}
+			prepareTableForBookmarks(state)
+			state.BookmarksTable.SetSelected(i - state.PageIndex)
+		})
+	})
+
+	tuiUI.SetKeybinding(model.DeleteBookmarkKeyBinding, func() {
+		if state.CurrentNavMode != model.BookmarksNavigationMode {
+			return
+		}
+		i := state.PageIndex + state.BookmarksTable.Selected()
+		if i >= len(state.Bookmarks) {
+			return
+		}
+		name := state.Bookmarks[i].Name
+		state.Bookmarks = append(state.Bookmarks[:i], state.Bookmarks[i+1:]...)
+		if state.PageIndex >= len(state.Bookmarks) && state.PageIndex >= model.PageSize {
+			state.PageIndex -= model.PageSize
+		}
+		prepareTableForBookmarks(state)
+		save(fmt.Sprintf("Deleted bookmark '%s'", name))
+	})
+
+	tuiUI.SetKeybinding("Right", func() {
+		if state.CurrentNavMode != model.BookmarksNavigationMode || state.PageIndex+model.PageSize >= len(state.Bookmarks) {
+			return
+		}
+		state.PageIndex += model.PageSize
+		prepareTableForBookmarks(state)
+	})
+	tuiUI.SetKeybinding("Left", func() {
+		if state.CurrentNavMode != model.BookmarksNavigationMode || state.PageIndex < model.PageSize {
+			return
+		}
+		state.PageIndex -= model.PageSize
+		prepareTableForBookmarks(state)
+	})
+
+	state.BookmarksTable.OnItemActivated(func(t *tui.Table) {
+		i := state.PageIndex + t.Selected()
+		if i >= len(state.Bookmarks) {
+			return
+		}
+		closeBookmarks(state)
+		navigation.JumpToLine(state, state.Bookmarks[i].Line)
+		chunk := text.GetChunk(&state.FileContent, state.From, state.To)
+		text.PutText(txtArea, &chunk, txtAreaScroll, state)
+		inputCommand.SetText(utils.GetStatusInformation(state))
+	})
+}
+
+// defaultBookmarkName names a bookmark after the chapter it is in, or its line otherwise.
+func defaultBookmarkName(line int, state *model.AppState) string {
+	if current := chapters.Current(state.Chapters, line); current >= 0 {
+		return fmt.Sprintf("%s, line %d", state.Chapters[current].Title, line)
+	}
+	return fmt.Sprintf("Line %d", line)
+}
+
+// addBookmark keeps the bookmarks in reading order.
+func addBookmark(state *model.AppState, bookmark model.Bookmark) {
+	i := sort.Search(len(state.Bookmarks), func(i int) bool { return state.Bookmarks[i].Line > bookmark.Line })
+	state.Bookmarks = append(state.Bookmarks, model.Bookmark{})
+	copy(state.Bookmarks[i+1:], state.Bookmarks[i:])
+	state.Bookmarks[i] = bookmark
+}
+
+func prepareTableForBookmarks(state *model.AppState) {
+	state.BookmarksTable.RemoveRows()
+	end := min(state.PageIndex+model.PageSize, len(state.Bookmarks))
+	if state.PageIndex >= end {
+		state.BookmarksTable.AppendRow(tui.NewLabel("No bookmarks"))
+	}
+	for _, bookmark := range state.Bookmarks[min(state.PageIndex, end):end] {
+		state.BookmarksTable.AppendRow(tui.NewLabel(fmt.Sprintf(" %7d  %s ", bookmark.Line, bookmark.Name)))
+	}
+	state.BookmarksTable.SetSelected(0)
+}
+
+// closeBookmarkName removes the name prompt, going back to the bookmark manager when a
+// bookmark was being renamed. The caller gives the focus back to the bookmarks table.
+func closeBookmarkName(txtReader *tui.Box, inputCommand *tui.Entry, state *model.AppState) {
+	txtReader.Remove(model.GotoWidgetIndex)
+	inputCommand.SetFocused(true)
+	state.CurrentNavMode = model.ReadingNavigationMode
+	if state.EditedBookmark >= 0 {
+		state.CurrentNavMode = model.BookmarksNavigationMode
+	}
+}
+
+func closeBookmarks(state *model.AppState) {
+	state.CurrentNavMode = model.ReadingNavigationMode
+	state.BookmarksTable.SetFocused(false)
+	state.BookmarksTable.RemoveRows()
+	state.Sidebar.SetTitle("")
+	state.Sidebar.SetBorder(false)
+}
@@ -0,0 +1,170 @@
+package keybindings
+
+import (
+	"fmt"
+	"strings"
+	"textreader/internal/dictionary"
+	"textreader/internal/model"
+	"textreader/internal/ui"
+	"textreader/internal/utils"
+	"textreader/internal/words"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/marcusolsson/tui-go/wordwrap"
+)
+
+// AddLookUpKeyBindings shows the definitions of the highlighted word in a panel: l looks it
+// up in every dictionary of the library in priority order, L asks the DICT server of cfg.
+// j/k and Alt+Up/Alt+Down scroll the definitions.
+func AddLookUpKeyBindings(tuiUI tui.UI, root *ui.KeyBox, txtReader *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, library *dictionary.Library, dir string, server dictionary.ServerConfig, state *model.AppState) {
+	var definitionScroll *tui.ScrollArea
+	show := func(title string, text string) {
+		list := tui.NewList()
+		// The panel has the width of the text, less its border.
+		list.AddItems(strings.Split(wrap(text, txtAreaScroll.Size().X-2), "\n")...)
+		definitionScroll = tui.NewScrollArea(list)
+		panel := tui.NewVBox(definitionScroll)
+		panel.SetBorder(true)
+		panel.SetTitle(title)
+		txtReader.Append(panel)
+		state.CurrentNavMode = model.DefinitionMode
+	}
+
+	root.HandleRune(model.LookUpWordKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return false
+		}
+		if library.Len() == 0 {
+			inputCommand.SetText(fmt.Sprintf("No dictionaries, add StarDict or dictd dictionaries to %s%s", dir, brokenInformation(library)))
+			return true
+		}
+		word, ok := highlightedWord(state)
+		if !ok {
+			inputCommand.SetText("No word to look up")
+			return true
+		}
+		definitions, err := library.Lookup(word)
+		if err != nil {
+			inputCommand.SetText(fmt.Sprintf("Error looking up '%s': %v", word, err))
+			return true
+		}
+		if len(definitions) == 0 {
+			inputCommand.SetText(fmt.Sprintf("'%s' is not in the dictionaries%s", word, brokenInformation(library)))
+			return true
+		}
+		show(fmt.Sprintf("%s (%d definitions)", word, len(definitions)), formatDefinitions(definitions))
+		return true
+	})
+
+	root.HandleRune(model.LookUpWordOnServerKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return false
+		}
+		if server.Address == "" {
+			inputCommand.SetText("No DICT server, set dict_server in the config file")
+			return true
+		}
+		word, ok := highlightedWord(state)
+		if !ok {
+			inputCommand.SetText("No word to look up")
+			return true
+		}
+		inputCommand.SetText(fmt.Sprintf("Looking up '%s' on %s...", word, server.Address))
+		// The server may take a while, the answer is shown once it arrives.
+		go func() {
+			definitions, matches, err := dictionary.Lookup(server, word)
+			tuiUI.Update(func() {
+				if state.CurrentNavMode != model.ReadingNavigationMode {
+					return
+				}
+				switch {
+				case err != nil:
+					inputCommand.SetText(fmt.Sprintf("Error looking up '%s': %v", word, err))
+				case len(definitions) > 0:
+					inputCommand.SetText(utils.GetStatusInformation(state))
+					show(fmt.Sprintf("%s (%d definitions from %s)", word, len(definitions), server.Address), formatDefinitions(definitions))
+				case len(matches) > 0:
+					inputCommand.SetText(utils.GetStatusInformation(state))
+					show(fmt.Sprintf("%s is not defined, %d similar words", word, len(matches)), formatMatches(matches))
+				default:
+					inputCommand.SetText(fmt.Sprintf("'%s' is not on %s", word, server.Address))
+				}
+			})
+		}()
+		return true
+	})
+
+	scroll := func(dy int) func() {
+		return func() {
+			if state.CurrentNavMode == model.DefinitionMode {
+				definitionScroll.Scroll(0, dy)
+			}
+		}
+	}
+	tuiUI.SetKeybinding(model.DownKeyBindingAlternative1, scroll(1))
+	tuiUI.SetKeybinding(model.UpKeyBindingAlternative1, scroll(-1))
+	tuiUI.SetKeybinding("Alt+Down", scroll(1))
+	tuiUI.SetKeybinding("Alt+Up", scroll(-1))
+}
+
+// brokenInformation tells how many dictionaries could not be read and why the first one.
+func brokenInformation(library *dictionary.Library) string {
+	broken := library.Broken()
+	switch len(broken) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(" (a dictionary could not be read: %v)", broken[0])
+	default:
+		return fmt.Sprintf(" (%d dictionaries could not be read: %v, ...)", len(broken), broken[0])
+	}
+}
+
+// highlightedWord returns the highlighted word without punctuation.
+func highlightedWord(state *model.AppState) (string, bool) {
+	line := state.From + state.CurrentHighlight
+	if line >= len(state.FileContent) {
+		return "", false
+	}
+	wordsList := words.ExtractWords(state.FileContent[line])
+	if state.CurrentWord >= len(wordsList) {
+		return "", false
+	}
+	word := strings.Trim(words.SanitizeWord(wordsList[state.CurrentWord]), "¿¡«»!'“”‘’—-")
+	return word, word != ""
+}
+
+// formatDefinitions writes the definitions under the name of their dictionary.
+func formatDefinitions(definitions []dictionary.Definition) string {
+	var b strings.Builder
+	for i, definition := range definitions {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", definition.Dictionary)
+		b.WriteString(definition.Text)
+	}
+	return b.String()
+}
+
+// formatMatches writes the words similar to the one looked up, by database.
+func formatMatches(matches []dictionary.Match) string {
+	var b strings.Builder
+	for i, match := range matches {
+		if i == 0 || match.Database != matches[i-1].Database {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "[%s]\n", match.Database)
+		}
+		fmt.Fprintf(&b, "  %s\n", match.Word)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func wrap(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	return wordwrap.WrapString(text, width)
+}
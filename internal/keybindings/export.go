@@ -0,0 +1,82 @@
+package keybindings
+
+import (
+	"fmt"
+	"os"
+	"textreader/internal/export"
+	"textreader/internal/file"
+	"textreader/internal/model"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// AddExportVocabularyKeyBinding sets up Alt+v to write the vocabulary of the book to
+// ~/ltbr/vocabulary, as Anki notes unless the configuration says otherwise, and Alt+e to write
+// the vocabulary of every book in the progress file there, as the export-vocab command does.
+func AddExportVocabularyKeyBinding(tuiUI tui.UI, fileName string, inputCommand *tui.Entry, cfg export.Config, state *model.AppState) {
+	tuiUI.SetKeybinding(model.ExportVocabularyKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode && state.CurrentNavMode != model.VocabularyNavigationMode {
+			return
+		}
+		if len(state.Vocabulary) == 0 {
+			inputCommand.SetText("No vocabulary to export")
+			return
+		}
+		path, err := exportVocabulary(fileName, cfg, state)
+		if err != nil {
+			inputCommand.SetText(fmt.Sprintf("Error exporting the vocabulary: %v", err))
+			return
+		}
+		inputCommand.SetText(fmt.Sprintf("%d words exported to %s", len(state.Vocabulary), path))
+	})
+
+	tuiUI.SetKeybinding(model.ExportAllVocabularyKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode && state.CurrentNavMode != model.VocabularyNavigationMode {
+			return
+		}
+		entries, err := file.LoadProgress()
+		if err != nil {
+			inputCommand.SetText(fmt.Sprintf("Error loading progress: %v", err))
+			return
+		}
+		words := export.Vocabulary(entries, "")
+		if len(words) == 0 {
+			inputCommand.SetText("No vocabulary to export")
+			return
+		}
+		path, err := writeVocabulary(file.GetDirectoryNameForFile("vocabulary", "all-books"), words, cfg)
+		if err != nil {
+			inputCommand.SetText(fmt.Sprintf("Error exporting the vocabulary: %v", err))
+			return
+		}
+		inputCommand.SetText(fmt.Sprintf("%d words exported to %s", len(words), path))
+	})
+}
+
+func exportVocabulary(fileName string, cfg export.Config, state *model.AppState) (string, error) {
+	words := export.BookWords(state.Metadata.Title, fileName, state.Vocabulary)
+	return writeVocabulary(file.GetDirectoryNameForFile("vocabulary", fileName), words, cfg)
+}
+
+// writeVocabulary writes words to name with the extension of the format of cfg.
+func writeVocabulary(name string, words []export.Word, cfg export.Config) (string, error) {
+	format := cfg.Format
+	if format == "" {
+		format = export.FormatAnki
+	}
+	extension, err := export.Extension(format)
+	if err != nil {
+		return "", err
+	}
+	path := name + extension
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := export.Write(f, format, words, cfg); err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
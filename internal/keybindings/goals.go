@@ -0,0 +1,39 @@
+package keybindings
+
+import (
+	"fmt"
+	"textreader/internal/model"
+	"textreader/internal/stats"
+	"textreader/internal/ui"
+	"textreader/internal/utils"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// AddGoalNotice tells in the command bar when the daily goal is reached. It has to be added
+// after the hooks recording the reading, so that it sees the last key.
+func AddGoalNotice(root *ui.KeyBox, inputCommand *tui.Entry, state *model.AppState) {
+	if !state.Goal.Set() {
+		return
+	}
+	reached := func(now time.Time) bool {
+		return stats.GoalProgress(stats.GoalSessions(state, now), state.Goal, now) >= state.Goal.Amount
+	}
+	// A goal reached before opening the book was already celebrated.
+	if now := time.Now(); reached(now) {
+		state.GoalReached = now
+	}
+
+	root.AfterKey(func() {
+		now := time.Now()
+		if stats.SameDay(state.GoalReached, now) || !reached(now) {
+			return
+		}
+		state.GoalReached = now
+		sessions := stats.GoalSessions(state, now)
+		inputCommand.SetText(fmt.Sprintf("Daily goal reached: %s, streak %s",
+			stats.FormatGoalProgress(state.Goal, stats.GoalProgress(sessions, state.Goal, now)),
+			utils.Days(stats.Streak(sessions, state.Goal, now))))
+	})
+}
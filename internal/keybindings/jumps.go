@@ -0,0 +1,106 @@
+package keybindings
+
+import (
+	"fmt"
+	"strings"
+	"textreader/internal/chapters"
+	"textreader/internal/model"
+	"textreader/internal/navigation"
+	"textreader/internal/text"
+	"textreader/internal/ui"
+	"textreader/internal/utils"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// AddJumpKeyBindings records the jumps of the view (goto, search, table of contents,
+// bookmarks, ...) and sets up Ctrl+O/Tab to go back and forward through them, and Alt+j to
+// list them.
+func AddJumpKeyBindings(tuiUI tui.UI, root *ui.KeyBox, txtArea *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+	state.Jumps.Seen = state.From
+	root.AfterKey(func() { navigation.TrackMoves(state) })
+
+	show := func() {
+		chunk := text.GetChunk(&state.FileContent, state.From, state.To)
+		text.PutText(txtArea, &chunk, txtAreaScroll, state)
+		inputCommand.SetText(fmt.Sprintf("%s | jump %d of %d", utils.GetStatusInformation(state),
+			state.Jumps.Index+1, len(state.Jumps.Positions)))
+	}
+
+	tuiUI.SetKeybinding(model.JumpBackKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return
+		}
+		if !navigation.JumpBack(state) {
+			inputCommand.SetText("At the oldest jump")
+			return
+		}
+		show()
+	})
+
+	tuiUI.SetKeybinding(model.JumpForwardKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return
+		}
+		if !navigation.JumpForward(state) {
+			inputCommand.SetText("At the newest jump")
+			return
+		}
+		show()
+	})
+
+	tuiUI.SetKeybinding(model.ShowJumpsKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return
+		}
+		if len(state.Jumps.Positions) == 0 {
+			inputCommand.SetText("No jumps yet")
+			return
+		}
+		state.CurrentNavMode = model.JumpsNavigationMode
+		state.Sidebar.SetTitle("Jumps")
+		state.Sidebar.SetBorder(true)
+		prepareTableForJumps(state)
+		state.JumpsTable.SetFocused(true)
+	})
+
+	state.JumpsTable.OnItemActivated(func(t *tui.Table) {
+		// The newest jumps are listed first.
+		i := len(state.Jumps.Positions) - 1 - t.Selected()
+		closeJumps(state)
+		navigation.JumpTo(state, i)
+		show()
+	})
+}
+
+// prepareTableForJumps lists the most recent jumps, newest first, with the chapter and the
+// beginning of the line they point to.
+func prepareTableForJumps(state *model.AppState) {
+	state.JumpsTable.RemoveRows()
+	positions := state.Jumps.Positions
+	for i := len(positions) - 1; i >= 0 && i >= len(positions)-model.PageSize; i-- {
+		line := positions[i]
+		where := ""
+		if current := chapters.Current(state.Chapters, line); current >= 0 {
+			where = state.Chapters[current].Title + ": "
+		}
+		preview := ""
+		if line < len(state.FileContent) {
+			preview = strings.TrimSpace(state.FileContent[line])
+		}
+		marker := " "
+		if i == state.Jumps.Index {
+			marker = ">"
+		}
+		state.JumpsTable.AppendRow(tui.NewLabel(fmt.Sprintf(" %s %7d  %s%s ", marker, line, where, shorten(preview, 30))))
+	}
+	state.JumpsTable.SetSelected(0)
+}
+
+func closeJumps(state *model.AppState) {
+	state.CurrentNavMode = model.ReadingNavigationMode
+	state.JumpsTable.SetFocused(false)
+	state.JumpsTable.RemoveRows()
+	state.Sidebar.SetTitle("")
+	state.Sidebar.SetBorder(false)
+}
@@ -7,18 +7,20 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"textreader/internal/file"
 	"textreader/internal/model"
-	"textreader/internal/progress"
+	"textreader/internal/navigation"
 	"textreader/internal/references"
+	"textreader/internal/stats"
 	"textreader/internal/terminal"
 	"textreader/internal/text"
 	"textreader/internal/ui"
 	"textreader/internal/utils"
 	"textreader/internal/words"
+	"time"
 
 	"github.com/atotto/clipboard"
 
@@ -62,6 +64,25 @@ func AddUpDownKeyBindings(txtArea *tui.Box, ui tui.UI, inputCommand *tui.Entry,
 	ui.SetKeybinding(model.UpKeyBindingAlternative1, AddUpBinding(txtArea, inputCommand, txtAreaScroll, state))
 }
 
+// AddPagingKeyBindings moves through the book a page or half a page at a time, see
+// navigation.PageDown.
+func AddPagingKeyBindings(txtArea *tui.Box, ui tui.UI, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+	page := func(move func(*model.AppState, navigation.Page)) func() {
+		return func() {
+			text.MovePage(txtArea, txtAreaScroll, state, move)
+			inputCommand.SetText(utils.GetStatusInformation(state))
+		}
+	}
+	pageDown := page(func(state *model.AppState, p navigation.Page) { navigation.PageDown(state, p, 1) })
+	ui.SetKeybinding(model.PageDownKeyBindingAlternative1, pageDown)
+	ui.SetKeybinding(model.PageDownKeyBindingAlternative2, pageDown)
+	ui.SetKeybinding(model.PageUpKeyBindingAlternative1, page(func(state *model.AppState, p navigation.Page) { navigation.PageUp(state, p, 1) }))
+	ui.SetKeybinding(model.HalfPageDownKeyBinding, page(func(state *model.AppState, p navigation.Page) { navigation.PageDown(state, p, 2) }))
+	ui.SetKeybinding(model.HalfPageUpKeyBinding, page(func(state *model.AppState, p navigation.Page) { navigation.PageUp(state, p, 2) }))
+	ui.SetKeybinding(model.HomeKeyBinding, page(navigation.PageHome))
+	ui.SetKeybinding(model.EndKeyBinding, page(navigation.PageEnd))
+}
+
 func AddHighlightUpDownKeyBindings(txtArea *tui.Box, ui tui.UI, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
 	ui.SetKeybinding(model.DownKeyBindingAlternative2, AddHighlightDownBinding(txtArea, inputCommand, txtAreaScroll, state)) // Down arrow
 	ui.SetKeybinding(model.UpKeyBindingAlternative2, AddHighlightUpBinding(txtArea, inputCommand, txtAreaScroll, state))     // Up arrow
@@ -113,14 +134,14 @@ func AddSaveStatusKeyBinding(ui tui.UI, fileName string, inputCommand *tui.Entry
 	})
 }
 
-func AddCloseApplicationKeyBinding(ui tui.UI, txtArea, txtReader *tui.Box, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+func AddCloseApplicationKeyBinding(ui tui.UI, txtArea, txtReader *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
 	ui.SetKeybinding(model.CloseApplicationKeyBindingAlternative1, func() {
 
 		switch state.CurrentNavMode {
 		case model.ShowReferencesNavigationMode:
+			state.CurrentNavMode = model.ReadingNavigationMode
 			chunk := text.GetChunk(&state.FileContent, state.From, state.To)
 			text.PutText(txtArea, &chunk, txtAreaScroll, state)
-			state.CurrentNavMode = model.ReadingNavigationMode
 		case model.AnalyzeAndFilterReferencesNavigationMode:
 			chunk := text.GetChunk(&state.FileContent, state.From, state.To)
 			text.PutText(txtArea, &chunk, txtAreaScroll, state)
@@ -137,10 +158,33 @@ func AddCloseApplicationKeyBinding(ui tui.UI, txtArea, txtReader *tui.Box, txtAr
 			state.VocabTable.RemoveRows()
 			state.Sidebar.SetTitle("")
 			state.Sidebar.SetBorder(false)
-		case model.GotoNavigationMode, model.ShowTimePercentagePointsMode, model.ShowHelpMode:
+		case model.GotoNavigationMode, model.ShowTimePercentagePointsMode, model.ShowHelpMode, model.DefinitionMode, model.ReviewMode:
 			txtReader.Remove(model.GotoWidgetIndex)
+			inputCommand.SetFocused(true)
 			state.CurrentNavMode = model.ReadingNavigationMode
+		case model.TOCNavigationMode:
+			closeTOC(state)
+		case model.BookmarksNavigationMode:
+			closeBookmarks(state)
+		case model.JumpsNavigationMode:
+			closeJumps(state)
+		case model.BookmarkNameNavigationMode:
+			closeBookmarkName(txtReader, inputCommand, state)
+			state.BookmarksTable.SetFocused(state.CurrentNavMode == model.BookmarksNavigationMode)
+		case model.SearchNavigationMode:
+			cancelSearch(txtReader, inputCommand, state)
+			chunk := text.GetChunk(&state.FileContent, state.From, state.To)
+			text.PutText(txtArea, &chunk, txtAreaScroll, state)
+			inputCommand.SetText(utils.GetStatusInformation(state))
 		default:
+			// Esc first dismisses the search highlights, then quits.
+			if state.Search.Active() {
+				clearSearch(state)
+				chunk := text.GetChunk(&state.FileContent, state.From, state.To)
+				text.PutText(txtArea, &chunk, txtAreaScroll, state)
+				inputCommand.SetText(utils.GetStatusInformation(state))
+				return
+			}
 			terminal.ClearScreen()
 			ui.Quit()
 		}
@@ -262,37 +306,38 @@ func AddOnSelectedReference(state *model.AppState) {
 	})
 }
 
-func AddGotoKeyBinding(tuiUI tui.UI, txtReader *tui.Box, state *model.AppState) {
-	tuiUI.SetKeybinding(model.GotoKeyBindingAlternative1, func() {
-		ui.AddGotoWidget(txtReader, state)
-	})
-}
-
-func AddCloseGotoBinding(ui tui.UI, inputCommand *tui.Entry, txtReader, txtArea *tui.Box, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
-	ui.SetKeybinding(model.CloseGotoKeyBindingAlternative1, func() {
-		// Go To the specified line
-		inputCommand.SetText(utils.GetStatusInformation(state))
-
-		gotoLineNumber := progress.GetNumberLineGoto(state.GotoLine)
-		gotoLineNumberDigits, err := strconv.ParseInt(gotoLineNumber, 10, 64)
-		if err != nil {
-			return
-		}
-		if int(gotoLineNumberDigits) < (len(state.FileContent) - state.Advance) {
-			state.From = int(gotoLineNumberDigits)
-			state.To = state.From + state.Advance
+// AddGotoKeyBinding opens the goto prompt with "g". Enter goes to the target typed, invalid
+// targets are reported in the command bar and leave the prompt open to fix them.
+func AddGotoKeyBinding(root *ui.KeyBox, inputCommand *tui.Entry, txtReader, txtArea *tui.Box, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+	root.HandleRune(model.GotoKeyBindingAlternative1, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return false
+		}
+		inputCommand.SetFocused(false)
+		ui.AddGotoWidget(txtReader, state, func(target string) {
+			line, err := navigation.ParseGoto(target, state)
+			if err != nil {
+				inputCommand.SetText(fmt.Sprintf("Go To: %v", err))
+				return
+			}
+			txtReader.Remove(model.GotoWidgetIndex)
+			inputCommand.SetFocused(true)
+			state.CurrentNavMode = model.ReadingNavigationMode
+			navigation.JumpToLine(state, line)
 			chunk := text.GetChunk(&state.FileContent, state.From, state.To)
 			text.PutText(txtArea, &chunk, txtAreaScroll, state)
 			inputCommand.SetText(utils.GetStatusInformation(state))
-		}
-		txtReader.Remove(model.GotoWidgetIndex)
-		inputCommand.SetText(utils.GetStatusInformation(state))
-		state.CurrentNavMode = model.ReadingNavigationMode
+		})
+		return true
 	})
 }
 
 func AddNewNoteKeyBinding(ui tui.UI, txtArea *tui.Box, inputCommand *tui.Entry, fileName string, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
 	ui.SetKeybinding(model.NewNoteKeyBindingAlternative1, func() {
+		// While there is a search, n goes to the next match.
+		if state.Search.Active() {
+			return
+		}
 
 		oldStdout, oldStdin, oldSterr := os.Stdout, os.Stdin, os.Stderr
 
@@ -400,7 +445,7 @@ func AddShowMinutesTakenToReachPercentagePointKeyBinding(ui tui.UI, txtReader *t
 		state.CurrentNavMode = model.ShowTimePercentagePointsMode
 
 		l := tui.NewList()
-		var strs []string
+		strs := readingStatsLines(state)
 
 		percentages := make([]int, 0)
 		for p := range state.MinutesToReachNextPercentagePoint {
@@ -424,6 +469,29 @@ func AddShowMinutesTakenToReachPercentagePointKeyBinding(ui tui.UI, txtReader *t
 	})
 }
 
+// readingStatsLines sums up the sessions of the book, the reading speed and the time left.
+func readingStatsLines(state *model.AppState) []string {
+	sessions := state.AllSessions(time.Now())
+	var total time.Duration
+	linesRead := 0
+	for _, session := range sessions {
+		total += session.End.Sub(session.Start)
+		linesRead += session.LinesRead
+	}
+	strs := []string{fmt.Sprintf("%d sessions, %.1f minutes reading, %d lines read", len(sessions), total.Minutes(), linesRead)}
+	if speed := stats.ReadingSpeed(sessions); speed.Known() {
+		left := stats.EstimateTimeLeft(state, speed)
+		strs = append(strs, fmt.Sprintf("%.0f words per minute, %.1f lines per minute", speed.WordsPerMinute, speed.LinesPerMinute))
+		if left.InChapter {
+			strs = append(strs, fmt.Sprintf("%s left in the chapter", stats.FormatDuration(left.Chapter)))
+		}
+		strs = append(strs, fmt.Sprintf("%s left in the book", stats.FormatDuration(left.Book)))
+	} else {
+		strs = append(strs, "Not enough reading yet to tell the speed")
+	}
+	return append(strs, "")
+}
+
 func AddShowHelpKeyBinding(ui tui.UI, txtReader *tui.Box, state *model.AppState) {
 	ui.SetKeybinding(model.ShowHelpKeyBinding, func() {
 		// Check if we are already in that mode ...
@@ -444,10 +512,15 @@ func AddShowHelpKeyBinding(ui tui.UI, txtReader *tui.Box, state *model.AppState)
 
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Go Down / Go Up",
 			model.DownKeyBindingAlternative1+"/"+model.UpKeyBindingAlternative1), &strs)
-		addKeyBindingDescription(fmt.Sprintf("%10s -> Go To", model.GotoKeyBindingAlternative1), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Page Down / Page Up",
+			"Space/"+model.PageDownKeyBindingAlternative2+"/"+model.PageUpKeyBindingAlternative1), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Half Page Down / Half Page Up",
+			model.HalfPageDownKeyBinding+"/"+model.HalfPageUpKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Beginning / End of the Book", model.HomeKeyBinding+"/"+model.EndKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Go To a line, 50%%, +200, -30, ch 12, p 143 or a bookmark", string(model.GotoKeyBindingAlternative1)), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> New Note", model.NewNoteKeyBindingAlternative1), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Show Status", model.ShowStatusKeyBinding), &strs)
-		addKeyBindingDescription(fmt.Sprintf("%10s -> Closes the Goto Dialog", model.CloseGotoKeyBindingAlternative1), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Goes to the target of the Goto Dialog", model.CloseGotoKeyBindingAlternative1), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Save Progress", model.SaveStatusKeyBindingAlternative1), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Shows Next Percentage Point Stats", model.NextPercentagePointKeyBindingAlternative1), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Shows the References Dialog", model.ShowReferencesKeyBindingAlternative1), &strs)
@@ -463,9 +536,24 @@ func AddShowHelpKeyBinding(ui tui.UI, txtReader *tui.Box, state *model.AppState)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Highlight Up", model.UpKeyBindingAlternative2), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Word Left / Word Right", "Left/Right"), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Copy Word to Clipboard", "c"), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Look the Word up in the Dictionaries", string(model.LookUpWordKeyBinding)), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Look the Word up in the DICT Server", string(model.LookUpWordOnServerKeyBinding)), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Save Word to Vocabulary", model.SaveVocabularyKeyBinding), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Show Vocabulary Dialog", model.ShowVocabularyKeyBinding), &strs)
 		addKeyBindingDescription(fmt.Sprintf("%10s -> Delete Selected Word from Vocabulary", "x"), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Review the Vocabulary Due (Space shows the answer, 0-5 grade it)", model.ReviewKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Export the Vocabulary to ~/ltbr/vocabulary", model.ExportVocabularyKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Export the Vocabulary of Every Book to ~/ltbr/vocabulary", model.ExportAllVocabularyKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Shows the Table of Contents", model.ShowTOCKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Bookmark the Highlighted Line", string(model.AddBookmarkKeyBinding)), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Shows the Bookmarks (e renames, x deletes)", string(model.ShowBookmarksKeyBinding)), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Jump Back / Jump Forward",
+			model.JumpBackKeyBinding+"/"+model.JumpForwardKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Shows the Recent Jumps", model.ShowJumpsKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Search", string(model.SearchKeyBinding)), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Changes the Search Mode", model.SearchModeKeyBinding), &strs)
+		addKeyBindingDescription(fmt.Sprintf("%10s -> Next / Previous Match",
+			string(model.NextSearchMatchKeyBinding)+"/"+string(model.PreviousSearchMatchKeyBinding)), &strs)
 
 		l.AddItems(strs...)
 		s := tui.NewScrollArea(l)
@@ -527,11 +615,16 @@ func AddSaveVocabularyKeyBinding(ui tui.UI, fileName string, inputCommand *tui.E
 		}
 		word := wordsList[state.CurrentWord]
 		word = words.SanitizeWord(word)
-		if words.Contains(state.Vocabulary, word) {
+		if vocabularyIndex(state.Vocabulary, word) >= 0 {
 			inputCommand.SetText(fmt.Sprintf("Word '%s' already in vocabulary", word))
 			return
 		}
-		state.Vocabulary = append(state.Vocabulary, word)
+		state.Vocabulary = append(state.Vocabulary, model.VocabEntry{
+			Word:     word,
+			Sentence: words.Sentence(state.FileContent, currentLineIndex, state.CurrentWord),
+			Line:     currentLineIndex,
+			Added:    time.Now(),
+		})
 		err := file.SaveStatus(fileName, state.From, state.To, state)
 		if err != nil {
 			inputCommand.SetText(fmt.Sprintf("Error saving vocabulary: %v", err))
@@ -547,13 +640,38 @@ func prepareTableForVocabulary(state *model.AppState) {
 	if len(paginatedVocabulary) == 0 {
 		state.VocabTable.AppendRow(tui.NewLabel("No vocabulary words saved"))
 	} else {
-		for _, word := range paginatedVocabulary {
-			state.VocabTable.AppendRow(tui.NewLabel(fmt.Sprintf("   %s   ", word)))
+		for _, entry := range paginatedVocabulary {
+			state.VocabTable.AppendRow(tui.NewLabel(fmt.Sprintf("   %-15s %s   ", entry.Word, shorten(entry.Sentence, 40))))
 		}
 	}
 	state.VocabTable.SetSelected(0)
 }
 
+// vocabularyIndex returns where word is in the vocabulary, -1 when it is not.
+func vocabularyIndex(vocabulary []model.VocabEntry, word string) int {
+	return slices.IndexFunc(vocabulary, func(entry model.VocabEntry) bool { return entry.Word == word })
+}
+
+// vocabularyInformation tells a word of the vocabulary along with where it was found.
+func vocabularyInformation(entry model.VocabEntry) string {
+	switch {
+	case entry.Line < 0:
+		return entry.Word
+	case entry.Sentence == "":
+		return fmt.Sprintf("%s (line %d)", entry.Word, entry.Line)
+	default:
+		return fmt.Sprintf("%s (line %d): %s", entry.Word, entry.Line, entry.Sentence)
+	}
+}
+
+// shorten cuts s to n characters, marking the cut.
+func shorten(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n]) + "…"
+	}
+	return s
+}
+
 func AddVocabularyNavigationKeyBindings(ui tui.UI, state *model.AppState, inputCommand *tui.Entry) {
 	ui.SetKeybinding("Right", func() {
 		if state.CurrentNavMode != model.VocabularyNavigationMode {
@@ -593,7 +711,7 @@ func AddVocabularyNavigationKeyBindings(ui tui.UI, state *model.AppState, inputC
 		}
 		if selected > 0 {
 			state.VocabTable.SetSelected(selected)
-			inputCommand.SetText(fmt.Sprintf("Selected: %s", paginatedVocabulary[selected-1]))
+			inputCommand.SetText(fmt.Sprintf("Selected: %s", vocabularyInformation(paginatedVocabulary[selected-1])))
 		} else {
 			inputCommand.SetText("At first word in page")
 		}
@@ -611,7 +729,7 @@ func AddVocabularyNavigationKeyBindings(ui tui.UI, state *model.AppState, inputC
 		}
 		if selected < len(paginatedVocabulary)-1 {
 			state.VocabTable.SetSelected(selected)
-			inputCommand.SetText(fmt.Sprintf("Selected: %s", paginatedVocabulary[selected+1]))
+			inputCommand.SetText(fmt.Sprintf("Selected: %s", vocabularyInformation(paginatedVocabulary[selected+1])))
 		} else {
 			inputCommand.SetText("At last word in page")
 		}
@@ -629,7 +747,7 @@ func AddVocabularyNavigationKeyBindings(ui tui.UI, state *model.AppState, inputC
 		}
 		if selected > 0 {
 			state.VocabTable.SetSelected(selected)
-			inputCommand.SetText(fmt.Sprintf("Selected: %s                       ", paginatedVocabulary[selected-1]))
+			inputCommand.SetText(fmt.Sprintf("Selected: %s                       ", vocabularyInformation(paginatedVocabulary[selected-1])))
 		} else {
 			inputCommand.SetText("At first word in page")
 		}
@@ -648,7 +766,7 @@ func AddVocabularyNavigationKeyBindings(ui tui.UI, state *model.AppState, inputC
 
 		if selected < len(paginatedVocabulary)-1 {
 			state.VocabTable.SetSelected(selected)
-			inputCommand.SetText(fmt.Sprintf("Selected: %s                       ", paginatedVocabulary[selected+1]))
+			inputCommand.SetText(fmt.Sprintf("Selected: %s                       ", vocabularyInformation(paginatedVocabulary[selected+1])))
 		} else {
 			inputCommand.SetText("At last word in page")
 		}
@@ -659,8 +777,8 @@ func AddVocabularyNavigationKeyBindings(ui tui.UI, state *model.AppState, inputC
 func AddOnSelectedVocabulary(state *model.AppState) {
 	state.VocabTable.OnItemActivated(func(t *tui.Table) {
 		itemIndexToRemove := t.Selected()
-		itemToAddToNonRefs := state.Vocabulary[state.PageIndex+itemIndexToRemove]
-		text.FindAndRemove(&state.Vocabulary, itemToAddToNonRefs)
+		itemToAddToNonRefs := state.Vocabulary[state.PageIndex+itemIndexToRemove].Word
+		state.Vocabulary = slices.Delete(state.Vocabulary, state.PageIndex+itemIndexToRemove, state.PageIndex+itemIndexToRemove+1)
 		prepareTableForVocabulary(state)
 		// ToDo: handle error
 		file.SaveStatus(state.FileToOpen, state.From, state.To, state)
@@ -684,8 +802,8 @@ func AddDeleteVocabularyKeyBinding(ui tui.UI, inputCommand *tui.Entry, state *mo
 			inputCommand.SetText("No word selected to delete")
 			return
 		}
-		itemToRemove := state.Vocabulary[state.PageIndex+itemIndexToRemove]
-		text.FindAndRemove(&state.Vocabulary, itemToRemove)
+		itemToRemove := state.Vocabulary[state.PageIndex+itemIndexToRemove].Word
+		state.Vocabulary = slices.Delete(state.Vocabulary, state.PageIndex+itemIndexToRemove, state.PageIndex+itemIndexToRemove+1)
 		prepareTableForVocabulary(state)
 		err := file.SaveStatus(state.FileToOpen, state.From, state.To, state)
 		if err != nil {
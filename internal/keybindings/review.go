@@ -0,0 +1,194 @@
+package keybindings
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"textreader/internal/dictionary"
+	"textreader/internal/file"
+	"textreader/internal/model"
+	"textreader/internal/review"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// maxDefinitionLines is how much of a definition the back of a card shows.
+const maxDefinitionLines = 8
+
+// AddReviewKeyBindings sets up r to review the words of every book that are due: the word is
+// shown, Space reveals where it was found and its definition, and 0 to 5 grade how well it was
+// remembered, which schedules its next review.
+func AddReviewKeyBindings(tuiUI tui.UI, txtReader *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, fileName string, library *dictionary.Library, state *model.AppState) {
+	var (
+		key      string
+		cards    []review.Card
+		queue    []int
+		total    int
+		revealed bool
+		card     *tui.Label
+		panel    *tui.Box
+	)
+
+	show := func() {
+		c := cards[queue[0]]
+		text := fmt.Sprintf("%s\n\nfrom %s", c.Entry.Word, c.Book)
+		if revealed {
+			text += "\n\n" + cardAnswer(c.Entry, library) +
+				"\n\nHow well did you remember it? 0-2 forgot, 3 hard, 4 good, 5 easy"
+		} else {
+			text += "\n\nSpace shows the answer"
+		}
+		card.SetText(wrap(text, txtAreaScroll.Size().X-4))
+		panel.SetTitle(fmt.Sprintf("Review: %d of %d", total-len(queue)+1, total))
+	}
+
+	tuiUI.SetKeybinding(model.ReviewKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return
+		}
+		var err error
+		if key, cards, err = loadCards(fileName, state); err != nil {
+			inputCommand.SetText(fmt.Sprintf("Error loading the vocabulary: %v", err))
+			return
+		}
+		due := review.Due(cards, time.Now())
+		if len(due) == 0 {
+			inputCommand.SetText("No words to review")
+			return
+		}
+		queue = queue[:0]
+		for _, c := range due {
+			queue = append(queue, cardIndex(cards, c))
+		}
+		total, revealed = len(queue), false
+
+		card = tui.NewLabel("")
+		panel = tui.NewVBox(card)
+		panel.SetBorder(true)
+		txtReader.Append(panel)
+		state.CurrentNavMode = model.ReviewMode
+		show()
+	})
+
+	tuiUI.SetKeybinding(model.ShowAnswerKeyBinding, func() {
+		if state.CurrentNavMode != model.ReviewMode || revealed {
+			return
+		}
+		revealed = true
+		show()
+	})
+
+	for grade := 0; grade <= review.MaxGrade; grade++ {
+		tuiUI.SetKeybinding(strconv.Itoa(grade), func() {
+			if state.CurrentNavMode != model.ReviewMode || !revealed {
+				return
+			}
+			i := queue[0]
+			review.Grade(&cards[i].Entry, grade, time.Now())
+			if err := file.UpdateVocabEntry(cards[i].Key, cards[i].Entry); err != nil {
+				inputCommand.SetText(fmt.Sprintf("Error saving the review: %v", err))
+				return
+			}
+			if cards[i].Key == key {
+				if j := vocabularyIndex(state.Vocabulary, cards[i].Entry.Word); j >= 0 {
+					state.Vocabulary[j] = cards[i].Entry
+				}
+			}
+			state.DueInOtherBooks = countDueInOtherBooks(cards, key)
+
+			// Forgotten words come back at the end of the review.
+			queue = queue[1:]
+			if grade < review.PassGrade {
+				queue = append(queue, i)
+			}
+			if len(queue) == 0 {
+				txtReader.Remove(model.GotoWidgetIndex)
+				inputCommand.SetFocused(true)
+				state.CurrentNavMode = model.ReadingNavigationMode
+				inputCommand.SetText(fmt.Sprintf("Review done, %d words reviewed", total))
+				return
+			}
+			revealed = false
+			show()
+		})
+	}
+}
+
+// loadCards returns the key of the book being read in the progress file and the words of
+// every book, the ones of the book being read taken from state.
+func loadCards(fileName string, state *model.AppState) (string, []review.Card, error) {
+	key, err := file.ProgressKey(fileName, state)
+	if err != nil {
+		return "", nil, err
+	}
+	entries, err := file.LoadProgress()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var cards []review.Card
+	title := state.Metadata.Title
+	if title == "" {
+		title = filepath.Base(fileName)
+	}
+	for _, entry := range state.Vocabulary {
+		cards = append(cards, review.Card{Key: key, Book: title, Entry: entry})
+	}
+	for entryKey, book := range entries {
+		if entryKey == key {
+			continue
+		}
+		title := book.Title
+		if title == "" {
+			title = filepath.Base(book.FileName)
+		}
+		for _, entry := range book.Vocabulary {
+			cards = append(cards, review.Card{Key: entryKey, Book: title, Entry: entry})
+		}
+	}
+	return key, cards, nil
+}
+
+func cardIndex(cards []review.Card, c review.Card) int {
+	for i := range cards {
+		if cards[i].Key == c.Key && cards[i].Entry.Word == c.Entry.Word {
+			return i
+		}
+	}
+	return -1
+}
+
+func countDueInOtherBooks(cards []review.Card, key string) int {
+	due, now := 0, time.Now()
+	for _, c := range cards {
+		if c.Key != key && review.IsDue(c.Entry, now) {
+			due++
+		}
+	}
+	return due
+}
+
+// cardAnswer writes the back of the card of entry: the sentence it was found in, its note and
+// its definition in the first dictionary that has it.
+func cardAnswer(entry model.VocabEntry, library *dictionary.Library) string {
+	var parts []string
+	if entry.Sentence != "" {
+		parts = append(parts, fmt.Sprintf("“%s”", entry.Sentence))
+	}
+	if entry.Note != "" {
+		parts = append(parts, "Note: "+entry.Note)
+	}
+	if definitions, err := library.Lookup(entry.Word); err == nil && len(definitions) > 0 {
+		lines := strings.Split(definitions[0].Text, "\n")
+		if len(lines) > maxDefinitionLines {
+			lines = append(lines[:maxDefinitionLines], "…")
+		}
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", definitions[0].Dictionary, strings.Join(lines, "\n")))
+	}
+	if len(parts) == 0 {
+		return "No context saved for this word"
+	}
+	return strings.Join(parts, "\n\n")
+}
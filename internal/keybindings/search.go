@@ -0,0 +1,135 @@
+package keybindings
+
+import (
+	"textreader/internal/model"
+	"textreader/internal/navigation"
+	"textreader/internal/search"
+	"textreader/internal/text"
+	"textreader/internal/ui"
+	"textreader/internal/utils"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// AddSearchKeyBindings sets up "/" to search the book as the query is typed, Tab to change
+// the search mode in the prompt and n/N to go to the next/previous match.
+func AddSearchKeyBindings(tuiUI tui.UI, root *ui.KeyBox, txtReader, txtArea *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+	refresh := func() {
+		chunk := text.GetChunk(&state.FileContent, state.From, state.To)
+		text.PutText(txtArea, &chunk, txtAreaScroll, state)
+		inputCommand.SetText(utils.GetStatusInformation(state))
+	}
+	// Incremental search: jump to the first match after where reading was when the prompt
+	// opened, and back there when nothing matches.
+	incremental := func() {
+		if len(state.Search.Matches) == 0 {
+			restoreOrigin(state)
+		} else {
+			jumpToMatch(state, firstMatchFrom(state.Search.Matches, state.Search.Origin))
+		}
+		refresh()
+	}
+
+	root.HandleRune(model.SearchKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return false
+		}
+		state.Search.Origin = state.From
+		updateSearch(state, "")
+		inputCommand.SetFocused(false)
+		ui.AddSearchWidget(txtReader, state, func(query string) {
+			updateSearch(state, query)
+			incremental()
+		}, func() {
+			txtReader.Remove(model.GotoWidgetIndex)
+			inputCommand.SetFocused(true)
+			state.CurrentNavMode = model.ReadingNavigationMode
+			refresh()
+		})
+		refresh()
+		return true
+	})
+
+	tuiUI.SetKeybinding(model.SearchModeKeyBinding, func() {
+		if state.CurrentNavMode != model.SearchNavigationMode {
+			return
+		}
+		state.Search.Mode = state.Search.Mode.Next()
+		updateSearch(state, state.Search.Query)
+		incremental()
+	})
+
+	root.HandleRune(model.NextSearchMatchKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode || !state.Search.Active() {
+			return false
+		}
+		if n := len(state.Search.Matches); n > 0 {
+			jumpToMatch(state, (state.Search.Current+1)%n)
+		}
+		refresh()
+		return true
+	})
+
+	root.HandleRune(model.PreviousSearchMatchKeyBinding, func() bool {
+		if state.CurrentNavMode != model.ReadingNavigationMode || !state.Search.Active() {
+			return false
+		}
+		if n := len(state.Search.Matches); n > 0 {
+			jumpToMatch(state, (state.Search.Current-1+n)%n)
+		}
+		refresh()
+		return true
+	})
+}
+
+// cancelSearch closes the search prompt, going back to where reading was before it opened.
+func cancelSearch(txtReader *tui.Box, inputCommand *tui.Entry, state *model.AppState) {
+	txtReader.Remove(model.GotoWidgetIndex)
+	inputCommand.SetFocused(true)
+	state.CurrentNavMode = model.ReadingNavigationMode
+	clearSearch(state)
+	restoreOrigin(state)
+}
+
+func restoreOrigin(state *model.AppState) {
+	navigation.JumpToLine(state, state.Search.Origin)
+}
+
+// clearSearch forgets the query and its matches.
+func clearSearch(state *model.AppState) {
+	updateSearch(state, "")
+}
+
+func updateSearch(state *model.AppState, query string) {
+	s := &state.Search
+	s.Query, s.Matcher, s.Matches, s.Current, s.Err = query, nil, nil, 0, ""
+	if query == "" {
+		return
+	}
+	matcher, err := search.Compile(query, s.Mode)
+	if err != nil {
+		s.Err = err.Error()
+		return
+	}
+	s.Matcher = matcher
+	// Matches are found in the lines as shown, where PutText highlights them.
+	lines := make([]string, len(state.FileContent))
+	for i, line := range state.FileContent {
+		lines[i] = text.DisplayLine(line)
+	}
+	s.Matches = matcher.FindAll(lines)
+}
+
+func firstMatchFrom(matches []search.Match, line int) int {
+	for i, m := range matches {
+		if m.Line >= line {
+			return i
+		}
+	}
+	return 0
+}
+
+func jumpToMatch(state *model.AppState, i int) {
+	state.Search.Current = i
+	navigation.ScrollToLine(state, state.Search.Matches[i].Line)
+}
@@ -0,0 +1,94 @@
+package keybindings
+
+import (
+	"fmt"
+	"textreader/internal/chapters"
+	"textreader/internal/model"
+	"textreader/internal/navigation"
+	"textreader/internal/text"
+	"textreader/internal/utils"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// AddShowTOCKeyBinding shows the table of contents in the sidebar, on the page of the
+// chapter being read.
+func AddShowTOCKeyBinding(ui tui.UI, inputCommand *tui.Entry, state *model.AppState) {
+	ui.SetKeybinding(model.ShowTOCKeyBinding, func() {
+		if state.CurrentNavMode != model.ReadingNavigationMode {
+			return
+		}
+		if len(state.Chapters) == 0 {
+			inputCommand.SetText("No chapters found")
+			return
+		}
+		state.CurrentNavMode = model.TOCNavigationMode
+		state.Sidebar.SetTitle("Contents")
+		state.Sidebar.SetBorder(true)
+		current := max(chapters.Current(state.Chapters, state.From), 0)
+		state.PageIndex = current / model.PageSize * model.PageSize
+		prepareTableForTOC(state)
+		state.TOCTable.SetSelected(current - state.PageIndex)
+		state.TOCTable.SetFocused(true)
+	})
+}
+
+func AddTOCNavigationKeyBindings(ui tui.UI, state *model.AppState) {
+	ui.SetKeybinding("Right", func() {
+		if state.CurrentNavMode != model.TOCNavigationMode {
+			return
+		}
+		if state.PageIndex+model.PageSize >= len(state.Chapters) {
+			return
+		}
+		state.PageIndex += model.PageSize
+		prepareTableForTOC(state)
+	})
+	ui.SetKeybinding("Left", func() {
+		if state.CurrentNavMode != model.TOCNavigationMode {
+			return
+		}
+		if state.PageIndex < model.PageSize {
+			return
+		}
+		state.PageIndex -= model.PageSize
+		prepareTableForTOC(state)
+	})
+}
+
+// AddOnSelectedTOC jumps to the chapter activated in the table of contents.
+func AddOnSelectedTOC(txtArea *tui.Box, inputCommand *tui.Entry, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
+	state.TOCTable.OnItemActivated(func(t *tui.Table) {
+		i := state.PageIndex + t.Selected()
+		if i >= len(state.Chapters) {
+			return
+		}
+		closeTOC(state)
+		navigation.JumpToLine(state, state.Chapters[i].Line)
+		chunk := text.GetChunk(&state.FileContent, state.From, state.To)
+		text.PutText(txtArea, &chunk, txtAreaScroll, state)
+		inputCommand.SetText(utils.GetStatusInformation(state))
+	})
+}
+
+func prepareTableForTOC(state *model.AppState) {
+	state.TOCTable.RemoveRows()
+	current := chapters.Current(state.Chapters, state.From)
+	end := min(state.PageIndex+model.PageSize, len(state.Chapters))
+	for i := state.PageIndex; i < end; i++ {
+		marker := " "
+		if i == current {
+			marker = ">"
+		}
+		state.TOCTable.AppendRow(tui.NewLabel(fmt.Sprintf(" %s %s ", marker, state.Chapters[i].Title)))
+	}
+	state.TOCTable.SetSelected(0)
+}
+
+func closeTOC(state *model.AppState) {
+	state.CurrentNavMode = model.ReadingNavigationMode
+	state.TOCTable.SetFocused(false)
+	state.TOCTable.RemoveRows()
+	state.Sidebar.SetTitle("")
+	state.Sidebar.SetBorder(false)
+}
@@ -0,0 +1,176 @@
+package markup
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var htmlBlockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true, "footer": true,
+	"tr": true, "dt": true, "dd": true, "figure": true, "figcaption": true, "table": true,
+}
+
+type htmlList struct {
+	ordered bool
+	counter int
+}
+
+// NewHTMLDecoder returns a lenient XML decoder able to tokenize real world HTML. The input
+// is expected to be UTF-8 already, whatever its declaration says.
+func NewHTMLDecoder(r io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
+
+// RenderHTML lays out an (X)HTML document as plain lines of at most width columns.
+func RenderHTML(r io.Reader, width int) (Rendered, error) {
+	b := newBuilder(width)
+	decoder := NewHTMLDecoder(r)
+
+	var current strings.Builder
+	var lists []htmlList
+	headingLevel := 0
+	quoteDepth := 0
+	skipDepth := 0
+	preDepth := 0
+	href := ""
+
+	flush := func() {
+		s := current.String()
+		current.Reset()
+		switch {
+		case headingLevel > 0:
+			b.heading(headingLevel, s)
+		case len(lists) > 0:
+			list := &lists[len(lists)-1]
+			marker := bullet
+			if list.ordered {
+				marker = strconv.Itoa(list.counter) + ". "
+			}
+			b.listItem(s, len(lists)-1, marker)
+		case quoteDepth > 0:
+			prefix := strings.Repeat("│ ", quoteDepth)
+			b.paragraph(s, prefix, prefix, paragraphBlock)
+		default:
+			b.paragraph(s, "", "", paragraphBlock)
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Rendered{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case name == "head" || name == "script" || name == "style" || name == "title":
+				skipDepth++
+			case skipDepth > 0:
+			case isHeading(name):
+				flush()
+				headingLevel = int(name[1] - '0')
+			case name == "ul" || name == "ol":
+				flush()
+				lists = append(lists, htmlList{ordered: name == "ol"})
+			case name == "li":
+				flush()
+				if len(lists) > 0 {
+					lists[len(lists)-1].counter++
+				}
+			case name == "blockquote":
+				flush()
+				quoteDepth++
+			case name == "pre":
+				flush()
+				preDepth++
+			case name == "br":
+				current.WriteString("\n")
+			case name == "hr":
+				flush()
+				b.rule()
+			case name == "a":
+				href = attr(t, "href")
+			case name == "img":
+				if alt := attr(t, "alt"); alt != "" {
+					current.WriteString(" [image: " + alt + "] ")
+				}
+			case htmlBlockElements[name]:
+				flush()
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case name == "head" || name == "script" || name == "style" || name == "title":
+				skipDepth--
+			case skipDepth > 0:
+			case isHeading(name):
+				flush()
+				headingLevel = 0
+			case name == "ul" || name == "ol":
+				flush()
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				if len(lists) == 0 {
+					b.last = breakList(b.last)
+				}
+			case name == "li":
+				flush()
+			case name == "blockquote":
+				flush()
+				quoteDepth--
+			case name == "pre":
+				for _, line := range strings.Split(strings.Trim(current.String(), "\n"), "\n") {
+					b.verbatim(line)
+				}
+				current.Reset()
+				preDepth--
+			case name == "a":
+				if href != "" {
+					current.WriteString(b.link("", href))
+				}
+				href = ""
+			case htmlBlockElements[name]:
+				flush()
+			}
+		case xml.CharData:
+			switch {
+			case skipDepth > 0:
+			case preDepth > 0:
+				current.Write(t)
+			default:
+				// Source line breaks are just whitespace, only <br> breaks a line.
+				current.WriteString(strings.ReplaceAll(string(t), "\n", " "))
+			}
+		}
+	}
+	flush()
+	return b.finish(), nil
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if strings.ToLower(a.Name.Local) == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func isHeading(name string) bool {
+	return len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6'
+}
@@ -0,0 +1,155 @@
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	mdHeadingRe    = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdSetextH1Re   = regexp.MustCompile(`^\s{0,3}=+\s*$`)
+	mdSetextH2Re   = regexp.MustCompile(`^\s{0,3}-+\s*$`)
+	mdRuleRe       = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_])){2,}\s*$`)
+	mdListItemRe   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdQuoteRe      = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdFenceRe      = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	mdImageRe      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	mdLinkRe       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdAutoLinkRe   = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	mdCodeSpanRe   = regexp.MustCompile("`+([^`]+?)`+")
+	mdStrongRe     = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	mdEmphasisRe   = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]($|[^\w*])`)
+	mdHTMLTagRe    = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdEscapeRe     = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!>])`)
+	mdLinkMarkerRe = regexp.MustCompile(`\x00(\d+)\x00`)
+)
+
+// RenderMarkdown lays out a Markdown document as plain lines of at most width columns.
+func RenderMarkdown(content string, width int) Rendered {
+	b := newBuilder(width)
+
+	var paragraph []string
+	listDepth, listMarker := -1, ""
+	quote := false
+	inFence := false
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		joined := inline(strings.Join(paragraph, "\n"), b)
+		switch {
+		case listDepth >= 0:
+			b.listItem(joined, listDepth, listMarker)
+		case quote:
+			b.paragraph(joined, "│ ", "│ ", paragraphBlock)
+		default:
+			b.paragraph(joined, "", "", paragraphBlock)
+		}
+		paragraph = nil
+		listDepth, quote = -1, false
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if mdFenceRe.MatchString(line) {
+			flush()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			b.verbatim(line)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+			b.last = breakList(b.last)
+		case len(paragraph) == 1 && listDepth < 0 && !quote && mdSetextH1Re.MatchString(line):
+			b.heading(1, inline(paragraph[0], b))
+			paragraph = nil
+		case len(paragraph) == 1 && listDepth < 0 && !quote && mdSetextH2Re.MatchString(line):
+			b.heading(2, inline(paragraph[0], b))
+			paragraph = nil
+		case mdRuleRe.MatchString(line):
+			flush()
+			b.rule()
+		case mdHeadingRe.MatchString(line):
+			flush()
+			m := mdHeadingRe.FindStringSubmatch(line)
+			b.heading(len(m[1]), inline(m[2], b))
+		case mdListItemRe.MatchString(line):
+			flush()
+			m := mdListItemRe.FindStringSubmatch(line)
+			listDepth = len(strings.ReplaceAll(m[1], "\t", "    ")) / 2
+			listMarker = bullet
+			if m[2][0] >= '0' && m[2][0] <= '9' {
+				listMarker = m[2] + " "
+			}
+			paragraph = append(paragraph, m[3])
+		case mdQuoteRe.MatchString(line):
+			if !quote {
+				flush()
+				quote = true
+			}
+			paragraph = append(paragraph, mdQuoteRe.FindStringSubmatch(line)[1])
+		case strings.HasPrefix(trimmed, "|"):
+			flush()
+			b.verbatim(trimmed)
+		case len(paragraph) == 0 && (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")):
+			b.verbatim(strings.TrimPrefix(strings.TrimPrefix(line, "    "), "\t"))
+		default:
+			if strings.HasSuffix(line, "  ") && len(paragraph) > 0 {
+				paragraph[len(paragraph)-1] += "\n"
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return b.finish()
+}
+
+// breakList makes the next list item start a new block after a blank line.
+func breakList(kind blockKind) blockKind {
+	if kind == listBlock {
+		return paragraphBlock
+	}
+	return kind
+}
+
+// inline removes Markdown inline markup, turning links into footnote references.
+func inline(s string, b *builder) string {
+	// Explicit line breaks ("  " at the end of a line) survive as "\n"; soft ones become spaces.
+	s = strings.ReplaceAll(s, "\n\n", "\x01")
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\x01", "\n")
+
+	s = mdCodeSpanRe.ReplaceAllString(s, "$1")
+	s = mdImageRe.ReplaceAllStringFunc(s, func(m string) string {
+		alt := mdImageRe.FindStringSubmatch(m)[1]
+		if alt == "" {
+			return "[image]"
+		}
+		return "[image: " + alt + "]"
+	})
+
+	// Links are numbered in a second pass so footnotes follow the reading order.
+	targets := make([]string, 0)
+	s = mdLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLinkRe.FindStringSubmatch(m)
+		targets = append(targets, sub[2])
+		return sub[1] + "\x00" + strconv.Itoa(len(targets)-1) + "\x00"
+	})
+	s = mdAutoLinkRe.ReplaceAllString(s, "$1")
+	s = mdHTMLTagRe.ReplaceAllString(s, "")
+	s = mdStrongRe.ReplaceAllString(s, "$2")
+	s = mdEmphasisRe.ReplaceAllString(s, "$1$2$3")
+	s = mdEscapeRe.ReplaceAllString(s, "$1")
+
+	return mdLinkMarkerRe.ReplaceAllStringFunc(s, func(m string) string {
+		i, _ := strconv.Atoi(mdLinkMarkerRe.FindStringSubmatch(m)[1])
+		return b.link("", targets[i])
+	})
+}
@@ -0,0 +1,139 @@
+package markup
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"textreader/internal/model"
+	"textreader/internal/wrap"
+)
+
+const (
+	HeadingStyle   = "heading"
+	FootnoteStyle  = "footnote"
+	PageBreakStyle = "pagebreak"
+
+	bullet = "• "
+	rule   = "────────────────────"
+)
+
+// Rendered is marked-up text laid out as plain lines, ready to be read like any text file.
+type Rendered struct {
+	Lines []string
+	// Styles maps a line index to the label style it is drawn with.
+	Styles map[int]string
+	// Chapters holds the top level headings (h1/h2).
+	Chapters []model.Chapter
+}
+
+type blockKind int
+
+const (
+	noBlock blockKind = iota
+	paragraphBlock
+	headingBlock
+	listBlock
+	verbatimBlock
+)
+
+// builder lays out blocks of text, keeping a single blank line between them and collecting
+// link targets so they can be listed as footnotes at the end.
+type builder struct {
+	width int
+	out   Rendered
+	last  blockKind
+	links []string
+}
+
+func newBuilder(width int) *builder {
+	return &builder{
+		width: width,
+		out:   Rendered{Lines: []string{}, Styles: map[int]string{}, Chapters: []model.Chapter{}},
+	}
+}
+
+func (b *builder) startBlock(kind blockKind) {
+	sameList := kind == listBlock && b.last == listBlock
+	sameVerbatim := kind == verbatimBlock && b.last == verbatimBlock
+	if len(b.out.Lines) > 0 && !sameList && !sameVerbatim && b.out.Lines[len(b.out.Lines)-1] != "" {
+		b.out.Lines = append(b.out.Lines, "")
+	}
+	b.last = kind
+}
+
+func (b *builder) heading(level int, title string) {
+	title = collapse(title)
+	if title == "" {
+		return
+	}
+	b.startBlock(headingBlock)
+	if level <= 2 {
+		b.out.Chapters = append(b.out.Chapters, model.Chapter{Title: title, Line: len(b.out.Lines)})
+	}
+	for _, line := range wrap.Paragraph(title, b.width) {
+		b.out.Styles[len(b.out.Lines)] = HeadingStyle
+		b.out.Lines = append(b.out.Lines, line)
+	}
+}
+
+// paragraph reflows s, keeping explicit line breaks ("\n"). Every line but the first gets rest
+// as its prefix.
+func (b *builder) paragraph(s, first, rest string, kind blockKind) {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(s, "\n") {
+		if part = collapse(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return
+	}
+	b.startBlock(kind)
+	prefix := first
+	for _, part := range parts {
+		for _, line := range wrap.Paragraph(part, b.width-len([]rune(rest))) {
+			b.out.Lines = append(b.out.Lines, prefix+line)
+			prefix = rest
+		}
+	}
+}
+
+func (b *builder) listItem(s string, depth int, marker string) {
+	indent := strings.Repeat("  ", depth)
+	b.paragraph(s, indent+marker, indent+strings.Repeat(" ", len([]rune(marker))), listBlock)
+}
+
+func (b *builder) verbatim(line string) {
+	b.startBlock(verbatimBlock)
+	b.out.Lines = append(b.out.Lines, "    "+strings.TrimRight(line, " \t"))
+}
+
+func (b *builder) rule() {
+	b.startBlock(paragraphBlock)
+	b.out.Lines = append(b.out.Lines, rule)
+}
+
+// link returns the text to show for a link, numbering external targets as footnotes.
+func (b *builder) link(label, target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme == "" || u.Scheme == "file" {
+		return label
+	}
+	b.links = append(b.links, target)
+	return fmt.Sprintf("%s[%d]", label, len(b.links))
+}
+
+func (b *builder) finish() Rendered {
+	if len(b.links) > 0 {
+		b.startBlock(paragraphBlock)
+		for i, target := range b.links {
+			b.out.Styles[len(b.out.Lines)] = FootnoteStyle
+			b.out.Lines = append(b.out.Lines, fmt.Sprintf("[%d] %s", i+1, target))
+		}
+	}
+	return b.out
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
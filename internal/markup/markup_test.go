@@ -0,0 +1,97 @@
+package markup
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"textreader/internal/model"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	content := `# El Aleph
+
+La candente mañana de febrero en que **Beatriz Viterbo**
+murió, después de una _imperiosa_ agonía, vi el [cartel](https://example.com/cartel).
+
+- primero
+- segundo con ` + "`código`" + `
+  y más
+
+1. uno
+
+Otra parte
+----------
+`
+
+	got := RenderMarkdown(content, 40)
+	want := []string{
+		"El Aleph",
+		"",
+		"La candente mañana de febrero en que",
+		"Beatriz Viterbo murió, después de una",
+		"imperiosa agonía, vi el cartel[1].",
+		"",
+		"• primero",
+		"• segundo con código y más",
+		"",
+		"1. uno",
+		"",
+		"Otra parte",
+		"",
+		"[1] https://example.com/cartel",
+	}
+	if !reflect.DeepEqual(got.Lines, want) {
+		t.Errorf("got=[%q], want=[%q]", got.Lines, want)
+	}
+
+	wantStyles := map[int]string{0: HeadingStyle, 11: HeadingStyle, 13: FootnoteStyle}
+	if !reflect.DeepEqual(got.Styles, wantStyles) {
+		t.Errorf("got=[%v], want=[%v]", got.Styles, wantStyles)
+	}
+
+	wantChapters := []model.Chapter{{Title: "El Aleph", Line: 0}, {Title: "Otra parte", Line: 11}}
+	if !reflect.DeepEqual(got.Chapters, wantChapters) {
+		t.Errorf("got=[%v], want=[%v]", got.Chapters, wantChapters)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	content := `<!DOCTYPE html>
+<html><head><title>ignored</title><style>p { color: red }</style></head>
+<body>
+<h1>Ficciones</h1>
+<p>Debo a la conjunción de un espejo y de una <a href="https://es.wikipedia.org/wiki/Enciclopedia">enciclopedia</a>
+el descubrimiento de Uqbar.</p>
+<ul><li>Tlön</li><li>Orbis <b>Tertius</b></li></ul>
+<ol><li>uno</li><li>dos</li></ol>
+<p>Ver <a href="#nota">la nota</a>.<br>Fin&nbsp;&amp; adiós</p>
+</body></html>`
+
+	got, err := RenderHTML(strings.NewReader(content), 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Ficciones",
+		"",
+		"Debo a la conjunción de un espejo y de una",
+		"enciclopedia[1] el descubrimiento de Uqbar.",
+		"",
+		"• Tlön",
+		"• Orbis Tertius",
+		"",
+		"1. uno",
+		"2. dos",
+		"",
+		"Ver la nota.",
+		"Fin & adiós",
+		"",
+		"[1] https://es.wikipedia.org/wiki/Enciclopedia",
+	}
+	if !reflect.DeepEqual(got.Lines, want) {
+		t.Errorf("got=[%q], want=[%q]", got.Lines, want)
+	}
+	if got.Styles[0] != HeadingStyle || len(got.Chapters) != 1 {
+		t.Errorf("expected a styled heading and one chapter, got styles=[%v] chapters=[%v]", got.Styles, got.Chapters)
+	}
+}
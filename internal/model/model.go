@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+	"textreader/internal/search"
 	"time"
 
 	"github.com/marcusolsson/tui-go"
@@ -12,18 +14,48 @@ type NavMode int
 // AppState holds the application state.
 type AppState struct {
 	From, To, FromForReferences, ToReferences, FromForVocabulary, ToForVocabulary int
-	GotoLine                                                                      string
 	FileToOpen                                                                    string
-	Vocabulary                                                                    []string
+	Encoding                                                                      string
+	Format                                                                        string
+	Vocabulary                                                                    []VocabEntry
 	PercentagePointStats, ToggleShowStatus                                        bool
 	References, FileContent, BannedWords                                          []string
 	CurrentNavMode                                                                NavMode
 	Sidebar                                                                       *tui.Box
-	RefsTable, VocabTable                                                         *tui.Table
+	RefsTable, VocabTable, TOCTable, BookmarksTable, JumpsTable                   *tui.Table
 	PageIndex, CurrentPercentage, Advance                                         int
 	MinutesToReachNextPercentagePoint                                             map[int]time.Duration
 	StartTime                                                                     time.Time
 	CurrentHighlight, CurrentWord                                                 int
+	Chapters                                                                      []Chapter
+	LineStyles                                                                    map[int]string
+	StripGutenberg                                                                bool
+	BodyStart, BodyEnd                                                            int
+	Metadata                                                                      BookMetadata
+	PageBreaks                                                                    []int
+	Search                                                                        SearchState
+	Bookmarks                                                                     []Bookmark
+	// EditedBookmark is the bookmark being renamed, -1 while a new one is named.
+	EditedBookmark int
+	Jumps          JumpList
+	// Session is the reading going on, Sessions the ones of earlier runs.
+	Session  ReadingSession
+	Sessions []ReadingSession
+	// WordCounts holds the number of words before each line, and of the whole book last.
+	WordCounts []int
+	// Goal is the daily reading goal, last reached at GoalReached. OtherSessions are the
+	// sessions of the other books, which count toward it too.
+	Goal          Goal
+	GoalReached   time.Time
+	OtherSessions []ReadingSession
+	// DueInOtherBooks counts the words of the other books to review.
+	DueInOtherBooks int
+}
+
+// TakesText tells whether the mode has a prompt the user types into, single key bindings
+// are disabled meanwhile.
+func (m NavMode) TakesText() bool {
+	return m == SearchNavigationMode || m == BookmarkNameNavigationMode || m == GotoNavigationMode
 }
 
 // NewAppState initializes a new AppState instance.
@@ -33,27 +65,155 @@ func NewAppState() *AppState {
 		To:                                0, // Will be set based on Advance
 		FromForReferences:                 0,
 		ToReferences:                      10,
-		GotoLine:                          "",
 		FileToOpen:                        "", // Initialize as empty string
+		Encoding:                          "auto",
 		PercentagePointStats:              false,
 		ToggleShowStatus:                  true,
 		References:                        []string{},
 		FileContent:                       []string{},
 		BannedWords:                       []string{},
 		CurrentNavMode:                    ReadingNavigationMode,
-		Vocabulary:                        []string{},
+		Vocabulary:                        []VocabEntry{},
 		Sidebar:                           tui.NewVBox(),
 		RefsTable:                         tui.NewTable(0, 0),
 		VocabTable:                        tui.NewTable(0, 0),
+		TOCTable:                          tui.NewTable(0, 0),
+		BookmarksTable:                    tui.NewTable(0, 0),
+		JumpsTable:                        tui.NewTable(0, 0),
 		PageIndex:                         0,
 		MinutesToReachNextPercentagePoint: make(map[int]time.Duration),
 		CurrentPercentage:                 0,
 		Advance:                           0,
 		CurrentHighlight:                  0,
 		CurrentWord:                       0,
+		Chapters:                          []Chapter{},
+		LineStyles:                        make(map[int]string),
+		StripGutenberg:                    true,
+		BodyStart:                         0,
+		BodyEnd:                           0, // Will be set once the file is loaded
+		PageBreaks:                        []int{},
+		Search:                            SearchState{Mode: search.IgnoreCase},
+		Bookmarks:                         []Bookmark{},
+		EditedBookmark:                    -1,
+		Jumps:                             JumpList{Positions: []int{}},
+		Sessions:                          []ReadingSession{},
 	}
 }
 
+// Chapter marks the line where a chapter of the book starts.
+type Chapter struct {
+	Title string
+	Line  int
+}
+
+// Bookmark is a named position in a book.
+type Bookmark struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+// VocabEntry is a word saved to the vocabulary, along with where and how it was used.
+type VocabEntry struct {
+	Word string `json:"word"`
+	// Sentence is the sentence of the book the word was saved from.
+	Sentence string `json:"sentence,omitempty"`
+	// Line is where the word was saved from, -1 for the words saved before it was recorded.
+	Line  int       `json:"line"`
+	Added time.Time `json:"added,omitzero"`
+	Note  string    `json:"note,omitempty"`
+	// Ease, Interval (in days) and Repetitions schedule the review of the word, next on Due,
+	// see the review package.
+	Ease        float64   `json:"ease,omitempty"`
+	Interval    int       `json:"interval,omitempty"`
+	Repetitions int       `json:"repetitions,omitempty"`
+	Due         time.Time `json:"due,omitzero"`
+}
+
+// UnmarshalJSON also reads the bare words vocabularies used to be saved as, so that the
+// progress file is migrated the next time it is saved.
+func (e *VocabEntry) UnmarshalJSON(data []byte) error {
+	var word string
+	if err := json.Unmarshal(data, &word); err == nil {
+		*e = VocabEntry{Word: word, Line: -1}
+		return nil
+	}
+	type entry VocabEntry
+	return json.Unmarshal(data, (*entry)(e))
+}
+
+// ReadingSession is a run of the reader on a book.
+type ReadingSession struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// LinesRead counts the lines scrolled forward through, jumps left out.
+	LinesRead int `json:"lines_read"`
+	WordsRead int `json:"words_read"`
+	// PercentagePoints is how much of the book the lines read are.
+	PercentagePoints float64 `json:"percentage_points,omitempty"`
+	// Active is the time spent reading, pauses longer than IdleTimeout left out.
+	Active time.Duration `json:"active"`
+	// LastActivity is when the last key was pressed.
+	LastActivity time.Time `json:"-"`
+}
+
+// Goal is an amount to read every day, e.g. {"unit": "minutes", "amount": 30}.
+type Goal struct {
+	Unit   string  `json:"unit"`
+	Amount float64 `json:"amount"`
+}
+
+// Set tells whether there is a goal.
+func (g Goal) Set() bool {
+	return g.Unit != "" && g.Amount > 0
+}
+
+// AllSessions returns the sessions of earlier runs followed by the current one, ending at
+// now, when something has been read in it.
+func (s *AppState) AllSessions(now time.Time) []ReadingSession {
+	sessions := make([]ReadingSession, 0, len(s.Sessions)+1)
+	sessions = append(sessions, s.Sessions...)
+	if current := s.Session; !current.Start.IsZero() && current.LinesRead > 0 {
+		current.End = now
+		sessions = append(sessions, current)
+	}
+	return sessions
+}
+
+// JumpList holds the positions the view jumped away from, like vim's jump list.
+type JumpList struct {
+	Positions []int
+	// Index is the position being visited with back/forward, len(Positions) when none is.
+	Index int
+	// Seen is where the view was after the last key, to tell jumps from scrolling.
+	Seen int
+}
+
+// SearchState is the full-text search in progress, if any.
+type SearchState struct {
+	Query   string
+	Mode    search.Mode
+	Matcher *search.Matcher
+	Matches []search.Match
+	// Current is the index in Matches of the match last jumped to.
+	Current int
+	// Err explains why the query could not be used, e.g. an incomplete regular expression.
+	Err string
+	// Origin is the line read before the prompt opened, restored if the search is cancelled.
+	Origin int
+}
+
+// Active tells whether there is a query whose matches are highlighted.
+func (s *SearchState) Active() bool {
+	return s.Matcher != nil
+}
+
+// BookMetadata describes a book, when its format tells us about it.
+type BookMetadata struct {
+	Title    string
+	Author   string
+	Language string
+}
+
 // LatestFile represents the latest file state.
 type LatestFile struct {
 	FileName string
@@ -66,10 +226,10 @@ const (
 	DownKeyBindingAlternative2                       = "Down"
 	UpKeyBindingAlternative1                         = "k"
 	UpKeyBindingAlternative2                         = "Up"
-	GotoKeyBindingAlternative1                       = "g"
+	GotoKeyBindingAlternative1                       = 'g'
 	NewNoteKeyBindingAlternative1                    = "n"
 	ShowStatusKeyBinding                             = "."
-	CloseGotoKeyBindingAlternative1                  = "r"
+	CloseGotoKeyBindingAlternative1                  = "Enter"
 	SaveStatusKeyBindingAlternative1                 = "s"
 	NextPercentagePointKeyBindingAlternative1        = "p"
 	ShowReferencesKeyBindingAlternative1             = "f"
@@ -83,6 +243,31 @@ const (
 	ShowHelpKeyBinding                               = "h"
 	SaveVocabularyKeyBinding                         = "w"
 	ShowVocabularyKeyBinding                         = "v"
+	SearchKeyBinding                                 = '/'
+	NextSearchMatchKeyBinding                        = 'n'
+	PreviousSearchMatchKeyBinding                    = 'N'
+	SearchModeKeyBinding                             = "Tab"
+	ShowTOCKeyBinding                                = "t"
+	AddBookmarkKeyBinding                            = 'b'
+	ShowBookmarksKeyBinding                          = 'B'
+	RenameBookmarkKeyBinding                         = "e"
+	DeleteBookmarkKeyBinding                         = "x"
+	JumpBackKeyBinding                               = "Ctrl+O"
+	JumpForwardKeyBinding                            = "Tab" // Terminals send Tab for Ctrl-I
+	ShowJumpsKeyBinding                              = "Alt+j"
+	PageDownKeyBindingAlternative1                   = " "
+	PageDownKeyBindingAlternative2                   = "PgDn"
+	PageUpKeyBindingAlternative1                     = "PgUp"
+	HalfPageDownKeyBinding                           = "Ctrl+D"
+	HalfPageUpKeyBinding                             = "Ctrl+U"
+	HomeKeyBinding                                   = "Home"
+	EndKeyBinding                                    = "End"
+	LookUpWordKeyBinding                             = 'l'
+	LookUpWordOnServerKeyBinding                     = 'L'
+	ReviewKeyBinding                                 = "r"
+	ShowAnswerKeyBinding                             = " "
+	ExportVocabularyKeyBinding                       = "Alt+v"
+	ExportAllVocabularyKeyBinding                    = "Alt+e"
 )
 
 const (
@@ -93,12 +278,37 @@ const (
 	ShowTimePercentagePointsMode             NavMode = 5
 	ShowHelpMode                             NavMode = 6
 	VocabularyNavigationMode                 NavMode = 7 // New navigation mode
+	SearchNavigationMode                     NavMode = 8
+	TOCNavigationMode                        NavMode = 9
+	BookmarksNavigationMode                  NavMode = 10
+	BookmarkNameNavigationMode               NavMode = 11
+	JumpsNavigationMode                      NavMode = 12
+	DefinitionMode                           NavMode = 13
+	ReviewMode                               NavMode = 14
+
+	// MaxJumps is how many positions the jump list remembers.
+	MaxJumps = 100
+
+	// Units of a daily Goal.
+	GoalMinutes    = "minutes"
+	GoalLines      = "lines"
+	GoalPercentage = "percentage"
+
+	// IdleTimeout is how long without pressing a key counts as a pause and not as reading.
+	IdleTimeout = 5 * time.Minute
 
 	GotoWidgetIndex = 2
 
 	NonRefsFileName = "non-refs.txt"
 
+	// StdinFileName is the -file value that reads the book from standard input.
+	StdinFileName = "-"
+
 	PageSize = 20
 
 	DBFileRequiredNumberFields = 3
+
+	// WrapWidth is the column at which paragraphs from structured formats (EPUB, ...) are
+	// wrapped. It is fixed so saved positions don't move when the terminal is resized.
+	WrapWidth = 78
 )
@@ -0,0 +1,109 @@
+package navigation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"textreader/internal/model"
+)
+
+var (
+	gotoLineRe     = regexp.MustCompile(`^\d+$`)
+	gotoPercentRe  = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*%$`)
+	gotoRelativeRe = regexp.MustCompile(`^([+-])\s*(\d+)$`)
+	gotoChapterRe  = regexp.MustCompile(`^(?:ch|chapter)\s*(\d+)$`)
+	gotoPageRe     = regexp.MustCompile(`^(?:p|page)\s*(\d+)$`)
+)
+
+// ParseGoto returns the line a goto target points to:
+//
+//	120     line 120
+//	50%     half of the book
+//	+200    200 lines after the top of the screen, -30 before it
+//	ch 12   the 12th chapter of the table of contents
+//	p 143   page 143, for books with pages
+//	name    the bookmark with that name, or starting with it
+func ParseGoto(input string, state *model.AppState) (int, error) {
+	target := strings.ToLower(strings.TrimSpace(input))
+	lines := len(state.FileContent)
+
+	var line int
+	switch {
+	case target == "":
+		return 0, fmt.Errorf("type a line, a percentage, +/-lines, ch N, p N or a bookmark name")
+	case gotoLineRe.MatchString(target):
+		line = atoi(target)
+	case gotoPercentRe.MatchString(target):
+		percent, _ := strconv.ParseFloat(gotoPercentRe.FindStringSubmatch(target)[1], 64)
+		if percent > 100 {
+			return 0, fmt.Errorf("%s is more than 100%%", input)
+		}
+		line = state.BodyStart + int(percent*float64(state.BodyEnd-state.BodyStart)/100)
+		line = min(line, lines-1)
+	case gotoRelativeRe.MatchString(target):
+		m := gotoRelativeRe.FindStringSubmatch(target)
+		offset := min(atoi(m[2]), lines)
+		if line = state.From + offset; m[1] == "-" {
+			line = max(state.From-offset, 0)
+		}
+	case gotoChapterRe.MatchString(target):
+		n := atoi(gotoChapterRe.FindStringSubmatch(target)[1])
+		if n < 1 || n > len(state.Chapters) {
+			return 0, fmt.Errorf("there is no chapter %d, the book has %d", n, len(state.Chapters))
+		}
+		line = state.Chapters[n-1].Line
+	case gotoPageRe.MatchString(target):
+		n := atoi(gotoPageRe.FindStringSubmatch(target)[1])
+		if len(state.PageBreaks) == 0 {
+			return 0, fmt.Errorf("the book has no pages")
+		}
+		if n < 1 || n > len(state.PageBreaks) {
+			return 0, fmt.Errorf("there is no page %d, the book has %d", n, len(state.PageBreaks))
+		}
+		line = state.PageBreaks[n-1]
+	default:
+		bookmark, err := findBookmark(target, state.Bookmarks)
+		if err != nil {
+			return 0, err
+		}
+		line = bookmark.Line
+	}
+
+	if line >= lines {
+		return 0, fmt.Errorf("line %d is past the end of the book, it has %d lines", line, lines)
+	}
+	return line, nil
+}
+
+// findBookmark looks a bookmark up by name, ignoring case, or by the beginning of its name
+// when no other bookmark starts the same way.
+func findBookmark(name string, bookmarks []model.Bookmark) (model.Bookmark, error) {
+	var found []model.Bookmark
+	for _, bookmark := range bookmarks {
+		bookmarkName := strings.ToLower(bookmark.Name)
+		if bookmarkName == name {
+			return bookmark, nil
+		}
+		if strings.HasPrefix(bookmarkName, name) {
+			found = append(found, bookmark)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return model.Bookmark{}, fmt.Errorf("%q is not a line, a percentage, a chapter, a page or a bookmark", name)
+	case 1:
+		return found[0], nil
+	default:
+		return model.Bookmark{}, fmt.Errorf("%q matches %d bookmarks", name, len(found))
+	}
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		// Only digits get here, too many of them.
+		return int(^uint(0) >> 1)
+	}
+	return n
+}
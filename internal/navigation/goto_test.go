@@ -0,0 +1,64 @@
+package navigation
+
+import (
+	"testing"
+	"textreader/internal/model"
+)
+
+func TestParseGoto(t *testing.T) {
+	state := newState(1000)
+	state.From = 400
+	state.BodyStart, state.BodyEnd = 100, 900
+	state.Chapters = []model.Chapter{{Title: "Uno", Line: 120}, {Title: "Dos", Line: 480}}
+	state.PageBreaks = []int{0, 50, 100}
+	state.Bookmarks = []model.Bookmark{{Name: "El duelo", Line: 610}, {Name: "El final", Line: 880}, {Name: "Mapa", Line: 20}}
+
+	type test struct {
+		input   string
+		want    int
+		wantErr bool
+	}
+
+	tests := []test{
+		{input: "250", want: 250},
+		{input: " 999 ", want: 999},
+		{input: "1000", wantErr: true},
+		{input: "99999999999999999999999", wantErr: true},
+		{input: "50%", want: 500},
+		{input: "12.5 %", want: 200},
+		{input: "100%", want: 900},
+		{input: "150%", wantErr: true},
+		{input: "+200", want: 600},
+		{input: "-30", want: 370},
+		{input: "-3000", want: 0},
+		{input: "+700", wantErr: true},
+		{input: "ch 2", want: 480},
+		{input: "CH1", want: 120},
+		{input: "chapter 3", wantErr: true},
+		{input: "p 2", want: 50},
+		{input: "page 4", wantErr: true},
+		{input: "el duelo", want: 610},
+		{input: "ma", want: 20},
+		{input: "el", wantErr: true},
+		{input: "nowhere", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseGoto(tc.input, state)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseGoto(%q) = %d, want an error", tc.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("ParseGoto(%q) = %d, %v, want %d", tc.input, got, err, tc.want)
+		}
+	}
+
+	state.PageBreaks = nil
+	if _, err := ParseGoto("p 1", state); err == nil {
+		t.Error("ParseGoto(p 1) should fail for books without pages")
+	}
+}
@@ -1,6 +1,9 @@
 package navigation
 
-import "textreader/internal/model"
+import (
+	"textreader/internal/model"
+	"textreader/internal/stats"
+)
 
 func UpdateRangesUp(state *model.AppState) {
 	if state.From <= 0 {
@@ -75,3 +78,182 @@ func UpdateRangesVocabularyDown(state *model.AppState) {
 		state.ToForVocabulary++
 	}
 }
+
+// JumpToLine shows the book from line on.
+func JumpToLine(state *model.AppState, line int) {
+	state.From = max(min(line, len(state.FileContent)-1), 0)
+	state.To = min(state.From+state.Advance, len(state.FileContent))
+	state.CurrentHighlight = 0
+	state.CurrentWord = 0
+}
+
+// ScrollToLine moves the reading window so that line is visible, leaving a few lines of
+// context above it when the window has to move.
+func ScrollToLine(state *model.AppState, line int) {
+	if line >= state.From && line < state.To {
+		return
+	}
+	JumpToLine(state, min(line-state.Advance/4, len(state.FileContent)-state.Advance))
+}
+
+// TrackMoves records where the view was when it moves by more than a screen at once, and
+// counts the lines read when it moves forward by a screen or less. It runs after every key,
+// except while a prompt is being typed into, so an incremental search only records the
+// position it started from.
+func TrackMoves(state *model.AppState) {
+	if state.CurrentNavMode.TakesText() {
+		return
+	}
+	jumps := &state.Jumps
+	switch distance := state.From - jumps.Seen; {
+	case distance > state.Advance || -distance > state.Advance:
+		RecordJump(state, jumps.Seen)
+	case distance > 0:
+		state.Session.LinesRead += distance
+		state.Session.WordsRead += stats.WordsBetween(state.WordCounts, jumps.Seen, state.From)
+		if body := state.BodyEnd - state.BodyStart; body > 0 {
+			state.Session.PercentagePoints += float64(distance) * 100 / float64(body)
+		}
+	}
+	jumps.Seen = state.From
+}
+
+// RecordJump adds line to the jump list, forgetting the positions ahead of the one being
+// visited, if any.
+func RecordJump(state *model.AppState, line int) {
+	jumps := &state.Jumps
+	positions := jumps.Positions[:min(jumps.Index, len(jumps.Positions))]
+	if n := len(positions); n == 0 || positions[n-1] != line {
+		positions = append(positions, line)
+	}
+	if len(positions) > model.MaxJumps {
+		positions = positions[len(positions)-model.MaxJumps:]
+	}
+	jumps.Positions = positions
+	jumps.Index = len(positions)
+}
+
+// JumpBack goes to the previous position of the jump list. The current position is kept so
+// that JumpForward can come back to it.
+func JumpBack(state *model.AppState) bool {
+	jumps := &state.Jumps
+	keepCurrentJump(state)
+	if jumps.Index <= 0 {
+		return false
+	}
+	jumps.Index--
+	visitJump(state)
+	return true
+}
+
+// JumpForward goes to the next position of the jump list, after going back.
+func JumpForward(state *model.AppState) bool {
+	jumps := &state.Jumps
+	if jumps.Index >= len(jumps.Positions)-1 {
+		return false
+	}
+	jumps.Index++
+	visitJump(state)
+	return true
+}
+
+// JumpTo goes to the i-th position of the jump list, as if going back or forward to it.
+func JumpTo(state *model.AppState, i int) {
+	jumps := &state.Jumps
+	if i < 0 || i >= len(jumps.Positions) {
+		return
+	}
+	keepCurrentJump(state)
+	jumps.Index = i
+	visitJump(state)
+}
+
+// keepCurrentJump adds the current position at the end of the jump list when leaving it for
+// an older one.
+func keepCurrentJump(state *model.AppState) {
+	jumps := &state.Jumps
+	if jumps.Index < len(jumps.Positions) {
+		return
+	}
+	if n := len(jumps.Positions); n == 0 || jumps.Positions[n-1] != state.From {
+		jumps.Positions = append(jumps.Positions, state.From)
+	}
+	jumps.Index = len(jumps.Positions) - 1
+}
+
+func visitJump(state *model.AppState) {
+	JumpToLine(state, state.Jumps.Positions[state.Jumps.Index])
+	state.Jumps.Seen = state.From
+}
+
+// Page tells how the text area shows the book: its height in screen rows and how many rows
+// each line of the book takes once wrapped.
+type Page struct {
+	Height int
+	Rows   func(line int) int
+}
+
+// linesFitting counts the lines from line on that fit in rows screen rows, at least one.
+func (p Page) linesFitting(state *model.AppState, line, rows int) int {
+	n := 0
+	for i := line; i < len(state.FileContent); i++ {
+		rows -= p.Rows(i)
+		if rows < 0 {
+			break
+		}
+		n++
+	}
+	return max(n, 1)
+}
+
+// linesFittingBefore counts the lines before line that fit in rows screen rows, at least one.
+func (p Page) linesFittingBefore(state *model.AppState, line, rows int) int {
+	n := 0
+	for i := line - 1; i >= 0; i-- {
+		rows -= p.Rows(i)
+		if rows < 0 {
+			break
+		}
+		n++
+	}
+	return max(n, 1)
+}
+
+// showFrom shows the lines from line on that fit in the page, so that wrapped lines never
+// push text out of the visible area.
+func (p Page) showFrom(state *model.AppState, line int) {
+	state.From = max(min(line, len(state.FileContent)-1), 0)
+	state.To = min(state.From+p.linesFitting(state, state.From, p.Height), len(state.FileContent))
+	state.CurrentHighlight = 0
+	state.CurrentWord = 0
+}
+
+// PageDown moves forward by a fraction of the page, 1 for a full page and 2 for half a page.
+// A full page starts with the first line that was not fully visible.
+func PageDown(state *model.AppState, page Page, fraction int) {
+	step := page.linesFitting(state, state.From, page.Height/fraction)
+	if state.From+step >= len(state.FileContent) {
+		return
+	}
+	page.showFrom(state, state.From+step)
+}
+
+// PageUp moves back by a fraction of the page, so that after a full page the line that was
+// at the top is the first line below the page.
+func PageUp(state *model.AppState, page Page, fraction int) {
+	if state.From <= 0 {
+		return
+	}
+	page.showFrom(state, state.From-page.linesFittingBefore(state, state.From, page.Height/fraction))
+}
+
+// PageHome shows the beginning of the book.
+func PageHome(state *model.AppState, page Page) {
+	page.showFrom(state, 0)
+}
+
+// PageEnd shows the last page of the book.
+func PageEnd(state *model.AppState, page Page) {
+	end := len(state.FileContent)
+	page.showFrom(state, end-page.linesFittingBefore(state, end, page.Height))
+}
@@ -0,0 +1,119 @@
+package navigation
+
+import (
+	"reflect"
+	"testing"
+	"textreader/internal/model"
+)
+
+func newState(lines int) *model.AppState {
+	state := model.NewAppState()
+	state.FileContent = make([]string, lines)
+	state.Advance = 10
+	JumpToLine(state, 0)
+	return state
+}
+
+func TestTrackMoves(t *testing.T) {
+	state := newState(1000)
+
+	// Scrolling a line at a time is not a jump.
+	for i := 0; i < 15; i++ {
+		UpdateRangesDown(state)
+		TrackMoves(state)
+	}
+	if len(state.Jumps.Positions) != 0 {
+		t.Fatalf("Positions = %v, want none after scrolling", state.Jumps.Positions)
+	}
+	if state.Session.LinesRead != 15 {
+		t.Fatalf("LinesRead = %d, want 15 after scrolling", state.Session.LinesRead)
+	}
+
+	JumpToLine(state, 500)
+	TrackMoves(state)
+	JumpToLine(state, 800)
+	TrackMoves(state)
+	if want := []int{15, 500}; !reflect.DeepEqual(state.Jumps.Positions, want) {
+		t.Fatalf("Positions = %v, want %v", state.Jumps.Positions, want)
+	}
+	if state.Session.LinesRead != 15 {
+		t.Errorf("LinesRead = %d, jumps should not count as read", state.Session.LinesRead)
+	}
+
+	// Typing in a prompt moves the view, only the starting point is recorded.
+	state.CurrentNavMode = model.SearchNavigationMode
+	JumpToLine(state, 100)
+	TrackMoves(state)
+	JumpToLine(state, 200)
+	TrackMoves(state)
+	state.CurrentNavMode = model.ReadingNavigationMode
+	TrackMoves(state)
+	if want := []int{15, 500, 800}; !reflect.DeepEqual(state.Jumps.Positions, want) {
+		t.Errorf("Positions = %v, want %v", state.Jumps.Positions, want)
+	}
+}
+
+func TestJumpBackAndForward(t *testing.T) {
+	state := newState(1000)
+	for _, line := range []int{100, 200, 300} {
+		JumpToLine(state, line)
+		TrackMoves(state)
+	}
+
+	var visited []int
+	for JumpBack(state) {
+		TrackMoves(state)
+		visited = append(visited, state.From)
+	}
+	for JumpForward(state) {
+		TrackMoves(state)
+		visited = append(visited, state.From)
+	}
+	if want := []int{200, 100, 0, 100, 200, 300}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited = %v, want %v", visited, want)
+	}
+
+	// A new jump after going back forgets the positions ahead.
+	JumpBack(state)
+	JumpBack(state)
+	JumpToLine(state, 900)
+	TrackMoves(state)
+	if want := []int{0, 100}; !reflect.DeepEqual(state.Jumps.Positions, want) {
+		t.Errorf("Positions = %v, want %v", state.Jumps.Positions, want)
+	}
+}
+
+func TestPaging(t *testing.T) {
+	state := newState(100)
+	// Every tenth line wraps into three rows.
+	page := Page{Height: 10, Rows: func(line int) int {
+		if line%10 == 5 {
+			return 3
+		}
+		return 1
+	}}
+
+	type test struct {
+		name     string
+		move     func()
+		from, to int
+	}
+
+	tests := []test{
+		{name: "home", move: func() { PageHome(state, page) }, from: 0, to: 8},
+		{name: "page down", move: func() { PageDown(state, page, 1) }, from: 8, to: 16},
+		{name: "half page down", move: func() { PageDown(state, page, 2) }, from: 13, to: 21},
+		{name: "page up", move: func() { PageUp(state, page, 1) }, from: 5, to: 13},
+		{name: "half page up", move: func() { PageUp(state, page, 2) }, from: 0, to: 8},
+		{name: "page up at the top", move: func() { PageUp(state, page, 1) }, from: 0, to: 8},
+		{name: "end", move: func() { PageEnd(state, page) }, from: 92, to: 100},
+		{name: "page down at the end", move: func() { PageDown(state, page, 1) }, from: 92, to: 100},
+	}
+
+	for _, tc := range tests {
+		tc.move()
+		if state.From != tc.from || state.To != tc.to {
+			t.Errorf("%s: got from=%d to=%d, want from=%d to=%d", tc.name, state.From, state.To, tc.from, tc.to)
+		}
+	}
+}
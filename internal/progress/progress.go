@@ -1,20 +1,19 @@
 package progress
 
-import "regexp"
-
-func GetNumberLineGoto(line string) string {
-	rgx, err := regexp.Compile("[^0-9]+")
-	if err != nil {
-		return ""
-	}
-	return rgx.ReplaceAllString(line, "")
-}
+import (
+	"sort"
+)
 
 func Percent(currentNumberLine, totalLines int) float64 {
 	return float64(currentNumberLine*100.0) / float64(totalLines)
 }
 
+// LinesToChangePercentagePoint tells how many lines are left to reach the next percentage
+// point, 0 when there are no lines to read.
 func LinesToChangePercentagePoint(currentLine, totalLines int) int {
+	if totalLines <= 0 {
+		return 0
+	}
 	start := currentLine
 	linesToChangePercentage := -1
 	percentageWithCurrentLine := int(Percent(currentLine, totalLines))
@@ -35,3 +34,24 @@ func GetPercentage(currentPosition int, fileContent *[]string) float64 {
 	percent = percent / float64(len(*fileContent))
 	return percent
 }
+
+// GetBookPercentage is like GetPercentage but only counts the body of the book, the lines in
+// [bodyStart, bodyEnd).
+func GetBookPercentage(currentPosition, bodyStart, bodyEnd int) float64 {
+	if bodyEnd <= bodyStart {
+		return 0
+	}
+	if currentPosition < bodyStart {
+		currentPosition = bodyStart
+	}
+	if currentPosition > bodyEnd {
+		currentPosition = bodyEnd
+	}
+	return Percent(currentPosition-bodyStart, bodyEnd-bodyStart)
+}
+
+// GetPageNumber returns the 1-based page a line belongs to, given the line where each page
+// starts, or 0 when the book has no pages.
+func GetPageNumber(line int, pageBreaks []int) int {
+	return sort.Search(len(pageBreaks), func(i int) bool { return pageBreaks[i] > line })
+}
@@ -42,7 +42,8 @@ func ExtractReferencesFromFileContent(fileContent *[]string, state *model.AppSta
 
 func LoadReferences(state *model.AppState) {
 	if len(state.References) == 0 {
-		state.References = ExtractReferencesFromFileContent(&state.FileContent, state)
+		body := state.FileContent[state.BodyStart:state.BodyEnd]
+		state.References = ExtractReferencesFromFileContent(&body, state)
 		state.ToReferences = terminal.CalculateTerminalHeight()
 	}
 }
@@ -0,0 +1,95 @@
+// Package review schedules the vocabulary for review with the SM-2 algorithm of SuperMemo.
+package review
+
+import (
+	"math"
+	"sort"
+	"textreader/internal/model"
+	"time"
+)
+
+const (
+	// DefaultEase is the ease of the words never reviewed.
+	DefaultEase = 2.5
+	// MinEase keeps hard words from being reviewed every day forever.
+	MinEase = 1.3
+	// MaxGrade is the grade of a perfect answer, grades under PassGrade are forgotten words.
+	MaxGrade  = 5
+	PassGrade = 3
+)
+
+// Grade schedules the next review of entry given how well it was remembered at now, from 0
+// (not at all) to 5 (perfectly).
+func Grade(entry *model.VocabEntry, grade int, now time.Time) {
+	grade = max(0, min(grade, MaxGrade))
+	ease := entry.Ease
+	if ease == 0 {
+		ease = DefaultEase
+	}
+
+	if grade < PassGrade {
+		entry.Repetitions = 0
+		entry.Interval = 1
+	} else {
+		entry.Repetitions++
+		switch entry.Repetitions {
+		case 1:
+			entry.Interval = 1
+		case 2:
+			entry.Interval = 6
+		default:
+			entry.Interval = int(math.Round(float64(entry.Interval) * ease))
+		}
+	}
+
+	miss := float64(MaxGrade - grade)
+	entry.Ease = max(MinEase, ease+0.1-miss*(0.08+miss*0.02))
+	entry.Due = startOfDay(now).AddDate(0, 0, entry.Interval)
+}
+
+// IsDue tells whether entry has to be reviewed at now. Words never reviewed are.
+func IsDue(entry model.VocabEntry, now time.Time) bool {
+	return !entry.Due.After(now)
+}
+
+// CountDue counts the words of vocabulary to review at now.
+func CountDue(vocabulary []model.VocabEntry, now time.Time) int {
+	due := 0
+	for _, entry := range vocabulary {
+		if IsDue(entry, now) {
+			due++
+		}
+	}
+	return due
+}
+
+// Card is a word to review, of the book of the progress file entry Key.
+type Card struct {
+	Key   string
+	Book  string
+	Entry model.VocabEntry
+}
+
+// Due returns the cards to review at now, the ones overdue longest first and the words
+// never reviewed last, in the order they were saved.
+func Due(cards []Card, now time.Time) []Card {
+	var due []Card
+	for _, card := range cards {
+		if IsDue(card.Entry, now) {
+			due = append(due, card)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		a, b := due[i].Entry, due[j].Entry
+		if a.Due.IsZero() != b.Due.IsZero() {
+			return b.Due.IsZero()
+		}
+		return a.Due.Before(b.Due)
+	})
+	return due
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,75 @@
+package review
+
+import (
+	"testing"
+	"textreader/internal/model"
+	"time"
+)
+
+func TestGrade(t *testing.T) {
+	now := time.Date(2024, 5, 10, 21, 30, 0, 0, time.Local)
+	tests := []struct {
+		name       string
+		grades     []int
+		interval   int
+		ease       float64
+		repetition int
+	}{
+		{name: "first review", grades: []int{4}, interval: 1, ease: 2.5, repetition: 1},
+		{name: "second review", grades: []int{4, 4}, interval: 6, ease: 2.5, repetition: 2},
+		{name: "third review", grades: []int{4, 4, 4}, interval: 15, ease: 2.5, repetition: 3},
+		{name: "easy answers", grades: []int{5, 5, 5}, interval: 16, ease: 2.8, repetition: 3},
+		{name: "forgotten", grades: []int{4, 4, 4, 1}, interval: 1, ease: 1.96, repetition: 0},
+		{name: "ease floor", grades: []int{0, 0, 0, 0, 0, 0}, interval: 1, ease: MinEase, repetition: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entry model.VocabEntry
+			for _, grade := range tt.grades {
+				Grade(&entry, grade, now)
+			}
+			if entry.Interval != tt.interval || entry.Repetitions != tt.repetition {
+				t.Errorf("Interval, Repetitions = %d, %d, want %d, %d", entry.Interval, entry.Repetitions, tt.interval, tt.repetition)
+			}
+			if diff := entry.Ease - tt.ease; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Ease = %v, want %v", entry.Ease, tt.ease)
+			}
+			if want := time.Date(2024, 5, 10+tt.interval, 0, 0, 0, 0, time.Local); !entry.Due.Equal(want) {
+				t.Errorf("Due = %v, want %v", entry.Due, want)
+			}
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+	cards := []Card{
+		{Key: "a", Entry: model.VocabEntry{Word: "nuevo"}},
+		{Key: "a", Entry: model.VocabEntry{Word: "mañana", Due: now.AddDate(0, 0, 1)}},
+		{Key: "b", Entry: model.VocabEntry{Word: "ayer", Due: now.AddDate(0, 0, -1)}},
+		{Key: "b", Entry: model.VocabEntry{Word: "semana", Due: now.AddDate(0, 0, -7)}},
+		{Key: "b", Entry: model.VocabEntry{Word: "otro"}},
+	}
+
+	var words []string
+	for _, card := range Due(cards, now) {
+		words = append(words, card.Entry.Word)
+	}
+	want := []string{"semana", "ayer", "nuevo", "otro"}
+	if len(words) != len(want) {
+		t.Fatalf("Due() = %v, want %v", words, want)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Fatalf("Due() = %v, want %v", words, want)
+		}
+	}
+
+	var vocabulary []model.VocabEntry
+	for _, card := range cards {
+		vocabulary = append(vocabulary, card.Entry)
+	}
+	if due := CountDue(vocabulary, now); due != 4 {
+		t.Errorf("CountDue() = %d, want 4", due)
+	}
+}
@@ -0,0 +1,152 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"unicode"
+)
+
+// Mode tells how a query is compared with the text.
+type Mode int
+
+const (
+	// Plain matches the query exactly.
+	Plain Mode = iota
+	// IgnoreCase matches regardless of letter case.
+	IgnoreCase
+	// IgnoreAccents matches regardless of letter case and diacritics: "cancion" finds "Canción".
+	IgnoreAccents
+	// Regex interprets the query as a Go regular expression.
+	Regex
+)
+
+var modeNames = map[Mode]string{
+	Plain:         "plain",
+	IgnoreCase:    "ignore case",
+	IgnoreAccents: "ignore accents",
+	Regex:         "regex",
+}
+
+func (m Mode) String() string {
+	return modeNames[m]
+}
+
+// Next returns the mode that follows m, cycling back to Plain after Regex.
+func (m Mode) Next() Mode {
+	return (m + 1) % Mode(len(modeNames))
+}
+
+// Match is an occurrence of the query: line index and byte offsets within the line.
+type Match struct {
+	Line, Start, End int
+}
+
+// Matcher finds a query in lines of text.
+type Matcher struct {
+	mode  Mode
+	query []rune
+	re    *regexp.Regexp
+}
+
+// Compile prepares query for the given mode. Only regular expressions can fail to compile.
+func Compile(query string, mode Mode) (*Matcher, error) {
+	m := &Matcher{mode: mode}
+	if mode == Regex {
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		m.re = re
+		return m, nil
+	}
+	for _, r := range query {
+		m.query = append(m.query, m.fold(r))
+	}
+	return m, nil
+}
+
+// FindLine returns the byte ranges of the non-overlapping occurrences in line.
+func (m *Matcher) FindLine(line string) [][2]int {
+	if m.re != nil {
+		spans := make([][2]int, 0)
+		for _, loc := range m.re.FindAllStringIndex(line, -1) {
+			if loc[1] > loc[0] {
+				spans = append(spans, [2]int{loc[0], loc[1]})
+			}
+		}
+		return spans
+	}
+	if len(m.query) == 0 {
+		return nil
+	}
+
+	// Folding maps every rune to exactly one rune, so rune positions in the folded text
+	// are rune positions in line.
+	runes := make([]rune, 0, len(line))
+	offsets := make([]int, 0, len(line)+1)
+	for i, r := range line {
+		runes = append(runes, m.fold(r))
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(line))
+
+	spans := make([][2]int, 0)
+	for i := 0; i+len(m.query) <= len(runes); {
+		if equalRunes(runes[i:i+len(m.query)], m.query) {
+			spans = append(spans, [2]int{offsets[i], offsets[i+len(m.query)]})
+			i += len(m.query)
+			continue
+		}
+		i++
+	}
+	return spans
+}
+
+// FindAll returns every match in lines, in reading order.
+func (m *Matcher) FindAll(lines []string) []Match {
+	matches := make([]Match, 0)
+	for i, line := range lines {
+		for _, span := range m.FindLine(line) {
+			matches = append(matches, Match{Line: i, Start: span[0], End: span[1]})
+		}
+	}
+	return matches
+}
+
+func (m *Matcher) fold(r rune) rune {
+	switch m.mode {
+	case IgnoreCase:
+		return unicode.ToLower(r)
+	case IgnoreAccents:
+		r = unicode.ToLower(r)
+		if base, ok := accentFolds[r]; ok {
+			return base
+		}
+	}
+	return r
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// accentFolds maps accented lowercase Latin letters to their base letter.
+var accentFolds = map[rune]rune{}
+
+func init() {
+	for base, accented := range map[rune]string{
+		'a': "àáâãäåāăą", 'c': "çćĉċč", 'd': "ďđ", 'e': "èéêëēĕėęě", 'g': "ĝğġģ", 'h': "ĥħ",
+		'i': "ìíîïĩīĭįı", 'j': "ĵ", 'k': "ķ", 'l': "ĺļľŀł", 'n': "ñńņňŉ", 'o': "òóôõöøōŏő",
+		'r': "ŕŗř", 's': "śŝşšș", 't': "ţťŧț", 'u': "ùúûüũūŭůűų", 'w': "ŵ", 'y': "ýÿŷ",
+		'z': "źżž",
+	} {
+		for _, r := range accented {
+			accentFolds[r] = base
+		}
+	}
+}
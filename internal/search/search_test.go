@@ -0,0 +1,69 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	lines := []string{
+		"La canción del pirata.",
+		"",
+		"Con diez cañones por banda, CANCION y cancion.",
+	}
+
+	type test struct {
+		query string
+		mode  Mode
+		want  []Match
+	}
+
+	tests := []test{
+		{query: "canción", mode: Plain, want: []Match{{Line: 0, Start: 3, End: 11}}},
+		{query: "Canción", mode: Plain, want: []Match{}},
+		{query: "CANCIÓN", mode: IgnoreCase, want: []Match{{Line: 0, Start: 3, End: 11}}},
+		{query: "cancion", mode: IgnoreAccents, want: []Match{
+			{Line: 0, Start: 3, End: 11},
+			{Line: 2, Start: 29, End: 36},
+			{Line: 2, Start: 39, End: 46},
+		}},
+		{query: "ca[nñ]", mode: Regex, want: []Match{
+			{Line: 0, Start: 3, End: 6},
+			{Line: 2, Start: 9, End: 13},
+			{Line: 2, Start: 39, End: 42},
+		}},
+		// Empty matches are of no use to highlight.
+		{query: "x*", mode: Regex, want: []Match{}},
+	}
+
+	for _, tc := range tests {
+		matcher, err := Compile(tc.query, tc.mode)
+		if err != nil {
+			t.Fatalf("Compile(%q, %s): %v", tc.query, tc.mode, err)
+		}
+		if got := matcher.FindAll(lines); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("FindAll(%q, %s) = %v, want %v", tc.query, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestCompileInvalidRegex(t *testing.T) {
+	if _, err := Compile("(unclosed", Regex); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+	if _, err := Compile("(unclosed", Plain); err != nil {
+		t.Errorf("plain queries should always compile, got %v", err)
+	}
+}
+
+func TestModeNext(t *testing.T) {
+	mode := Plain
+	seen := []Mode{}
+	for i := 0; i < 5; i++ {
+		seen = append(seen, mode)
+		mode = mode.Next()
+	}
+	if want := []Mode{Plain, IgnoreCase, IgnoreAccents, Regex, Plain}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("modes = %v, want %v", seen, want)
+	}
+}
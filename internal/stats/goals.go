@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"fmt"
+	"textreader/internal/model"
+	"time"
+)
+
+// ValidateGoal checks that goal has a known unit and a positive amount, when there is one.
+func ValidateGoal(goal model.Goal) error {
+	if goal == (model.Goal{}) {
+		return nil
+	}
+	switch goal.Unit {
+	case model.GoalMinutes, model.GoalLines, model.GoalPercentage:
+	default:
+		return fmt.Errorf("unknown goal unit %q, use %s, %s or %s", goal.Unit, model.GoalMinutes, model.GoalLines, model.GoalPercentage)
+	}
+	if goal.Amount <= 0 {
+		return fmt.Errorf("the goal amount must be positive, got %v", goal.Amount)
+	}
+	return nil
+}
+
+// GoalProgress tells how much of the goal unit has been read on the day of now.
+func GoalProgress(sessions []model.ReadingSession, goal model.Goal, now time.Time) float64 {
+	return dailyAmounts(sessions, goal)[now.Local().Format(dayLayout)]
+}
+
+// Streak counts the days in a row the goal was reached, up to today. Today does not break the
+// streak while it can still be reached.
+func Streak(sessions []model.ReadingSession, goal model.Goal, now time.Time) int {
+	if !goal.Set() {
+		return 0
+	}
+	amounts := dailyAmounts(sessions, goal)
+	now = now.Local()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if amounts[day.Format(dayLayout)] < goal.Amount {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for ; amounts[day.Format(dayLayout)] >= goal.Amount; day = day.AddDate(0, 0, -1) {
+		streak++
+	}
+	return streak
+}
+
+// GoalSessions returns the sessions counting toward the daily goal: the ones of the book
+// being read and of the other books.
+func GoalSessions(state *model.AppState, now time.Time) []model.ReadingSession {
+	return append(state.AllSessions(now), state.OtherSessions...)
+}
+
+// SameDay tells whether a and b are on the same local day.
+func SameDay(a, b time.Time) bool {
+	return a.Local().Format(dayLayout) == b.Local().Format(dayLayout)
+}
+
+// FormatGoalProgress writes how far along the goal is, "12/30 min".
+func FormatGoalProgress(goal model.Goal, progress float64) string {
+	switch goal.Unit {
+	case model.GoalMinutes:
+		return fmt.Sprintf("%.0f/%.0f min", progress, goal.Amount)
+	case model.GoalLines:
+		return fmt.Sprintf("%.0f/%.0f lines", progress, goal.Amount)
+	default:
+		return fmt.Sprintf("%.1f/%.0f%%", progress, goal.Amount)
+	}
+}
+
+// dailyAmounts adds up what the sessions read each day in the unit of goal, sessions counting
+// for the day they started.
+func dailyAmounts(sessions []model.ReadingSession, goal model.Goal) map[string]float64 {
+	amounts := make(map[string]float64)
+	for _, session := range sessions {
+		day := session.Start.Local().Format(dayLayout)
+		switch goal.Unit {
+		case model.GoalMinutes:
+			amounts[day] += sessionTime(session).Minutes()
+		case model.GoalLines:
+			amounts[day] += float64(session.LinesRead)
+		case model.GoalPercentage:
+			amounts[day] += session.PercentagePoints
+		}
+	}
+	return amounts
+}
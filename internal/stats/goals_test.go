@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"testing"
+	"textreader/internal/model"
+	"time"
+)
+
+func TestStreak(t *testing.T) {
+	now := time.Date(2024, 3, 10, 21, 0, 0, 0, time.Local)
+	read := func(daysAgo int, minutes time.Duration, lines int) model.ReadingSession {
+		start := now.AddDate(0, 0, -daysAgo)
+		return model.ReadingSession{Start: start, End: start.Add(minutes), Active: minutes, LinesRead: lines}
+	}
+	sessions := []model.ReadingSession{
+		read(5, 40*time.Minute, 400),
+		// Two sessions on the same day add up.
+		read(3, 10*time.Minute, 100),
+		read(3, 25*time.Minute, 250),
+		read(2, 30*time.Minute, 300),
+		read(1, 45*time.Minute, 500),
+		read(0, 10*time.Minute, 90),
+	}
+
+	type test struct {
+		goal     model.Goal
+		progress float64
+		streak   int
+	}
+
+	tests := []test{
+		// Today can still be reached, the streak goes on from yesterday.
+		{goal: model.Goal{Unit: model.GoalMinutes, Amount: 30}, progress: 10, streak: 3},
+		{goal: model.Goal{Unit: model.GoalLines, Amount: 90}, progress: 90, streak: 4},
+		{goal: model.Goal{Unit: model.GoalLines, Amount: 400}, progress: 90, streak: 1},
+		{goal: model.Goal{}, progress: 0, streak: 0},
+	}
+
+	for _, tc := range tests {
+		if got := GoalProgress(sessions, tc.goal, now); got != tc.progress {
+			t.Errorf("GoalProgress(%v) = %v, want %v", tc.goal, got, tc.progress)
+		}
+		if got := Streak(sessions, tc.goal, now); got != tc.streak {
+			t.Errorf("Streak(%v) = %d, want %d", tc.goal, got, tc.streak)
+		}
+	}
+}
+
+func TestValidateGoal(t *testing.T) {
+	valid := []model.Goal{{}, {Unit: model.GoalPercentage, Amount: 2.5}}
+	for _, goal := range valid {
+		if err := ValidateGoal(goal); err != nil {
+			t.Errorf("ValidateGoal(%v) = %v", goal, err)
+		}
+	}
+	invalid := []model.Goal{{Unit: "pages", Amount: 10}, {Unit: model.GoalMinutes}, {Amount: 10}}
+	for _, goal := range invalid {
+		if ValidateGoal(goal) == nil {
+			t.Errorf("ValidateGoal(%v) should fail", goal)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package stats measures reading speed and estimates the time left to finish a book.
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"textreader/internal/chapters"
+	"textreader/internal/model"
+	"time"
+)
+
+// minActive is the reading time needed before the speed is trusted.
+const minActive = time.Minute
+
+// CountWords returns the number of words before each line, with the total of words last, so
+// that the words between two lines are a subtraction away.
+func CountWords(lines []string) []int {
+	counts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		counts[i+1] = counts[i] + len(strings.Fields(line))
+	}
+	return counts
+}
+
+// WordsBetween returns the words in the lines [from, to) given the counts of CountWords.
+func WordsBetween(counts []int, from, to int) int {
+	from = max(0, min(from, len(counts)-1))
+	to = max(0, min(to, len(counts)-1))
+	if to <= from {
+		return 0
+	}
+	return counts[to] - counts[from]
+}
+
+// RecordActivity adds the time since the last key press to the active time of the session,
+// unless it was long enough to be a pause.
+func RecordActivity(session *model.ReadingSession, now time.Time) {
+	last := session.LastActivity
+	if last.IsZero() {
+		last = session.Start
+	}
+	if gap := now.Sub(last); gap > 0 && gap <= model.IdleTimeout {
+		session.Active += gap
+	}
+	session.LastActivity = now
+}
+
+// Speed is how fast the book is read.
+type Speed struct {
+	WordsPerMinute, LinesPerMinute float64
+}
+
+// Known tells whether enough has been read to tell the speed.
+func (s Speed) Known() bool {
+	return s.WordsPerMinute > 0
+}
+
+// ReadingSpeed measures the speed over the active time of sessions. Sessions saved before
+// the active time was recorded are left out.
+func ReadingSpeed(sessions []model.ReadingSession) Speed {
+	var active time.Duration
+	var words, lines int
+	for _, session := range sessions {
+		if session.Active <= 0 {
+			continue
+		}
+		active += session.Active
+		words += session.WordsRead
+		lines += session.LinesRead
+	}
+	if active < minActive || words == 0 {
+		return Speed{}
+	}
+	return Speed{
+		WordsPerMinute: float64(words) / active.Minutes(),
+		LinesPerMinute: float64(lines) / active.Minutes(),
+	}
+}
+
+// TimeLeft estimates how long the given words take to read, 0 when the speed is unknown.
+func (s Speed) TimeLeft(words int) time.Duration {
+	if !s.Known() {
+		return 0
+	}
+	return time.Duration(float64(words) / s.WordsPerMinute * float64(time.Minute))
+}
+
+// Estimate is the time left to finish the current chapter and the book.
+type Estimate struct {
+	Chapter, Book time.Duration
+	// InChapter is false for books without chapters.
+	InChapter bool
+}
+
+// EstimateTimeLeft estimates the time left from the bottom of the screen to the end of the
+// chapter and of the book, at the speed of all the sessions read.
+func EstimateTimeLeft(state *model.AppState, speed Speed) Estimate {
+	estimate := Estimate{Book: speed.TimeLeft(WordsBetween(state.WordCounts, state.To, state.BodyEnd))}
+	current := chapters.Current(state.Chapters, state.From)
+	if current < 0 {
+		return estimate
+	}
+	end := state.BodyEnd
+	if current+1 < len(state.Chapters) {
+		end = state.Chapters[current+1].Line
+	}
+	estimate.Chapter = speed.TimeLeft(WordsBetween(state.WordCounts, state.To, end))
+	estimate.InChapter = true
+	return estimate
+}
+
+// FormatDuration writes d in hours and minutes, "2 h 05 min" or "12 min".
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%d h %02d min", minutes/60, minutes%60)
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"testing"
+	"textreader/internal/model"
+	"time"
+)
+
+func TestWordsBetween(t *testing.T) {
+	counts := CountWords([]string{"En un lugar", "", "de la Mancha,", "  de cuyo  nombre "})
+
+	type test struct {
+		from, to, want int
+	}
+
+	tests := []test{
+		{from: 0, to: 4, want: 9},
+		{from: 1, to: 3, want: 3},
+		{from: 3, to: 1, want: 0},
+		{from: -5, to: 100, want: 9},
+	}
+
+	for _, tc := range tests {
+		if got := WordsBetween(counts, tc.from, tc.to); got != tc.want {
+			t.Errorf("WordsBetween(%d, %d) = %d, want %d", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestRecordActivity(t *testing.T) {
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	session := model.ReadingSession{Start: start}
+
+	RecordActivity(&session, start.Add(time.Minute))
+	RecordActivity(&session, start.Add(3*time.Minute))
+	// A pause, the book was left open.
+	RecordActivity(&session, start.Add(time.Hour))
+	RecordActivity(&session, start.Add(time.Hour+30*time.Second))
+
+	if want := 3*time.Minute + 30*time.Second; session.Active != want {
+		t.Errorf("Active = %v, want %v", session.Active, want)
+	}
+}
+
+func TestEstimateTimeLeft(t *testing.T) {
+	if ReadingSpeed([]model.ReadingSession{{Active: 30 * time.Second, WordsRead: 200}}).Known() {
+		t.Error("half a minute of reading should not be enough to tell the speed")
+	}
+
+	speed := ReadingSpeed([]model.ReadingSession{
+		{Active: 10 * time.Minute, WordsRead: 2000, LinesRead: 150},
+		// Saved before the active time was recorded.
+		{LinesRead: 999},
+		{Active: 10 * time.Minute, WordsRead: 3000, LinesRead: 250},
+	})
+	if speed.WordsPerMinute != 250 || speed.LinesPerMinute != 20 {
+		t.Fatalf("speed = %+v, want 250 words and 20 lines per minute", speed)
+	}
+
+	// Ten words per line.
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = "uno dos tres cuatro cinco seis siete ocho nueve diez"
+	}
+	state := model.NewAppState()
+	state.FileContent = lines
+	state.WordCounts = CountWords(lines)
+	state.From, state.To = 100, 150
+	state.BodyStart, state.BodyEnd = 0, 900
+	state.Chapters = []model.Chapter{{Title: "Uno", Line: 0}, {Title: "Dos", Line: 400}}
+
+	got := EstimateTimeLeft(state, speed)
+	want := Estimate{Chapter: 10 * time.Minute, Book: 30 * time.Minute, InChapter: true}
+	if got != want {
+		t.Errorf("EstimateTimeLeft() = %+v, want %+v", got, want)
+	}
+
+	state.Chapters = nil
+	if got := EstimateTimeLeft(state, speed); got.InChapter || got.Book != 30*time.Minute {
+		t.Errorf("EstimateTimeLeft() = %+v without chapters", got)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                               "0 min",
+		12*time.Minute + 20*time.Second: "12 min",
+		2*time.Hour + 5*time.Minute:     "2 h 05 min",
+	}
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
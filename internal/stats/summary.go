@@ -0,0 +1,211 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"textreader/internal/model"
+	"time"
+)
+
+// dayLayout is how days are written in Summary.Days.
+const dayLayout = "2006-01-02"
+
+// Book is what the progress file records of a book.
+type Book struct {
+	FileName, Title, Author string
+	Percentage              int
+	Sessions                []model.ReadingSession
+}
+
+// BookSummary sums up the reading of a book.
+type BookSummary struct {
+	Title          string  `json:"title"`
+	FileName       string  `json:"file_name"`
+	Sessions       int     `json:"sessions"`
+	Minutes        float64 `json:"minutes"`
+	WordsPerMinute float64 `json:"words_per_minute,omitempty"`
+	LinesPerMinute float64 `json:"lines_per_minute,omitempty"`
+	Percentage     int     `json:"percentage"`
+	Finished       bool    `json:"finished"`
+	// LastRead is when the last session ended, nil for books without sessions.
+	LastRead *time.Time `json:"last_read,omitempty"`
+}
+
+// Summary sums up the reading of every book in the progress file.
+type Summary struct {
+	Books          []BookSummary `json:"books"`
+	Sessions       int           `json:"sessions"`
+	Minutes        float64       `json:"minutes"`
+	WordsPerMinute float64       `json:"words_per_minute,omitempty"`
+	LinesPerMinute float64       `json:"lines_per_minute,omitempty"`
+	BooksFinished  int           `json:"books_finished"`
+	// Days holds the minutes read each day, keyed by date (2006-01-02).
+	Days map[string]float64 `json:"days"`
+}
+
+// Summarize sums up the books of the progress file, the ones read last first.
+func Summarize(entries []Book) Summary {
+	summary := Summary{Books: []BookSummary{}, Days: map[string]float64{}}
+	var all []model.ReadingSession
+	for _, entry := range entries {
+		book := BookSummary{
+			Title:      bookTitle(entry),
+			FileName:   entry.FileName,
+			Sessions:   len(entry.Sessions),
+			Percentage: entry.Percentage,
+			Finished:   entry.Percentage >= 100,
+		}
+		for _, session := range entry.Sessions {
+			minutes := sessionTime(session).Minutes()
+			book.Minutes += minutes
+			summary.Days[session.Start.Local().Format(dayLayout)] += minutes
+			if book.LastRead == nil || session.End.After(*book.LastRead) {
+				end := session.End
+				book.LastRead = &end
+			}
+		}
+		speed := ReadingSpeed(entry.Sessions)
+		book.WordsPerMinute, book.LinesPerMinute = speed.WordsPerMinute, speed.LinesPerMinute
+
+		summary.Books = append(summary.Books, book)
+		summary.Sessions += book.Sessions
+		summary.Minutes += book.Minutes
+		if book.Finished {
+			summary.BooksFinished++
+		}
+		all = append(all, entry.Sessions...)
+	}
+	speed := ReadingSpeed(all)
+	summary.WordsPerMinute, summary.LinesPerMinute = speed.WordsPerMinute, speed.LinesPerMinute
+
+	sort.Slice(summary.Books, func(i, j int) bool {
+		a, b := summary.Books[i], summary.Books[j]
+		if last, other := lastRead(a), lastRead(b); !last.Equal(other) {
+			return last.After(other)
+		}
+		return a.FileName < b.FileName
+	})
+	return summary
+}
+
+// lastRead is when book was read last, the zero time when it never was.
+func lastRead(book BookSummary) time.Time {
+	if book.LastRead == nil {
+		return time.Time{}
+	}
+	return *book.LastRead
+}
+
+// sessionTime is the time spent reading in a session, all of it for the sessions saved
+// before the active time was recorded.
+func sessionTime(session model.ReadingSession) time.Duration {
+	if session.Active > 0 {
+		return session.Active
+	}
+	return max(0, session.End.Sub(session.Start))
+}
+
+func bookTitle(entry Book) string {
+	switch {
+	case entry.Title == "":
+		return filepath.Base(entry.FileName)
+	case entry.Author == "":
+		return entry.Title
+	default:
+		return fmt.Sprintf("%s, %s", entry.Title, entry.Author)
+	}
+}
+
+// WriteSummary prints the summary of every book and of all of them, followed by the calendar
+// of the weeks up to now.
+func WriteSummary(w io.Writer, summary Summary, now time.Time, weeks int) error {
+	var b strings.Builder
+	for _, book := range summary.Books {
+		status := fmt.Sprintf("%d%% read", book.Percentage)
+		if book.Finished {
+			status = "finished"
+		}
+		fmt.Fprintf(&b, "%s\n  %s, %d sessions, %s%s\n", book.Title, status, book.Sessions,
+			FormatDuration(minutes(book.Minutes)), speedInformation(book.WordsPerMinute, book.LinesPerMinute))
+	}
+	if len(summary.Books) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d books, %d finished, %d sessions, %s%s\n\n", len(summary.Books), summary.BooksFinished,
+		summary.Sessions, FormatDuration(minutes(summary.Minutes)), speedInformation(summary.WordsPerMinute, summary.LinesPerMinute))
+	writeCalendar(&b, summary.Days, now, weeks)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func speedInformation(wordsPerMinute, linesPerMinute float64) string {
+	if wordsPerMinute == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %.0f words per minute, %.1f lines per minute", wordsPerMinute, linesPerMinute)
+}
+
+func minutes(m float64) time.Duration {
+	return time.Duration(m * float64(time.Minute))
+}
+
+// calendarLevels shade a day by the minutes read: none, less than 15, 30, 60 and more.
+var calendarLevels = []struct {
+	below  float64
+	symbol string
+}{
+	{below: 1, symbol: "·"},
+	{below: 15, symbol: "░"},
+	{below: 30, symbol: "▒"},
+	{below: 60, symbol: "▓"},
+}
+
+// writeCalendar draws the minutes read each day of the last weeks, a column per week from
+// Monday to Sunday, the current one last.
+func writeCalendar(b *strings.Builder, days map[string]float64, now time.Time, weeks int) {
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	first := monday.AddDate(0, 0, -7*(weeks-1))
+
+	// Month names over the week they start in, the first week named only when there is room
+	// before the next month.
+	header := []rune(strings.Repeat(" ", 4+weeks+3))
+	for week := 0; week < weeks; week++ {
+		start := first.AddDate(0, 0, 7*week)
+		newMonth := start.Month() != start.AddDate(0, 0, -7).Month()
+		if week == 0 {
+			newMonth = start.Month() == start.AddDate(0, 0, 21).Month()
+		}
+		if newMonth {
+			copy(header[4+week:], []rune(start.Format("Jan")))
+		}
+	}
+	b.WriteString(strings.TrimRight(string(header), " ") + "\n")
+
+	for weekday := 0; weekday < 7; weekday++ {
+		b.WriteString(first.AddDate(0, 0, weekday).Format("Mon")[:2] + "  ")
+		for week := 0; week < weeks; week++ {
+			day := first.AddDate(0, 0, 7*week+weekday)
+			if day.After(today) {
+				break
+			}
+			b.WriteString(calendarSymbol(days[day.Format(dayLayout)]))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n    · none  ░ <15 min  ▒ <30 min  ▓ <1 h  █ 1 h or more\n")
+}
+
+func calendarSymbol(minutes float64) string {
+	for _, level := range calendarLevels {
+		if minutes < level.below {
+			return level.symbol
+		}
+	}
+	return "█"
+}
@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"textreader/internal/model"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	day := time.Date(2024, 3, 1, 20, 0, 0, 0, time.Local)
+	books := []Book{
+		{FileName: "/libros/quijote.txt", Title: "Don Quijote", Author: "Cervantes", Percentage: 45, Sessions: []model.ReadingSession{
+			{Start: day, End: day.Add(time.Hour), Active: 30 * time.Minute, WordsRead: 3000, LinesRead: 300},
+			// Saved before the active time was recorded.
+			{Start: day.AddDate(0, 0, 1), End: day.AddDate(0, 0, 1).Add(20 * time.Minute), LinesRead: 100},
+		}},
+		{FileName: "/libros/fin.txt", Percentage: 100, Sessions: []model.ReadingSession{
+			{Start: day.AddDate(0, 0, 5), End: day.AddDate(0, 0, 5).Add(time.Hour), Active: 30 * time.Minute, WordsRead: 6000, LinesRead: 600},
+		}},
+		{FileName: "/libros/nuevo.txt"},
+	}
+
+	summary := Summarize(books)
+	if summary.Sessions != 3 || summary.Minutes != 80 || summary.BooksFinished != 1 {
+		t.Errorf("got %d sessions, %.0f minutes and %d books finished, want 3, 80 and 1",
+			summary.Sessions, summary.Minutes, summary.BooksFinished)
+	}
+	if summary.WordsPerMinute != 150 || summary.LinesPerMinute != 15 {
+		t.Errorf("got %.1f words and %.1f lines per minute, want 150 and 15", summary.WordsPerMinute, summary.LinesPerMinute)
+	}
+
+	var titles []string
+	for _, book := range summary.Books {
+		titles = append(titles, book.Title)
+	}
+	if got, want := strings.Join(titles, "|"), "fin.txt|Don Quijote, Cervantes|nuevo.txt"; got != want {
+		t.Errorf("books = %s, want %s", got, want)
+	}
+	if got := summary.Days["2024-03-02"]; got != 20 {
+		t.Errorf("minutes read on 2024-03-02 = %.0f, want 20", got)
+	}
+
+	// Books never read have no last_read in the JSON.
+	data, err := json.Marshal(summary.Books[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "last_read") {
+		t.Errorf("got=[%s], want=[no last_read]", data)
+	}
+	if want := day.AddDate(0, 0, 5).Add(time.Hour); summary.Books[0].LastRead == nil || !summary.Books[0].LastRead.Equal(want) {
+		t.Errorf("got=[%v], want=[%v]", summary.Books[0].LastRead, want)
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.Local)
+	days := map[string]float64{"2024-02-26": 10, "2024-03-05": 45, "2024-03-06": 90}
+
+	var b strings.Builder
+	writeCalendar(&b, days, now, 2)
+	rows := strings.Split(b.String(), "\n")
+	want := []string{"Mo  ░·", "Tu  ·▓", "We  ·█", "Th  ·", "Su  ·"}
+	for _, w := range want {
+		found := false
+		for _, row := range rows {
+			found = found || row == w
+		}
+		if !found {
+			t.Errorf("calendar has no row %q:\n%s", w, b.String())
+		}
+	}
+}
@@ -3,6 +3,7 @@ package terminal
 import (
 	"os"
 	"os/exec"
+	"runtime"
 
 	"golang.org/x/term"
 )
@@ -24,3 +25,22 @@ func CalculateTerminalHeight() int {
 
 	return advance
 }
+
+func IsStdinTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// ReopenStdin points os.Stdin back to the controlling terminal once piped input has been
+// consumed, so editors launched from the reader can still take keyboard input.
+func ReopenStdin() error {
+	ttyName := "/dev/tty"
+	if runtime.GOOS == "windows" {
+		ttyName = "CONIN$"
+	}
+	tty, err := os.Open(ttyName)
+	if err != nil {
+		return err
+	}
+	os.Stdin = tty
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"textreader/internal/words"
 
 	"github.com/marcusolsson/tui-go"
+	"github.com/marcusolsson/tui-go/wordwrap"
 )
 
 func PutText(box *tui.Box, content *[]string, txtAreaScroll *tui.ScrollArea, state *model.AppState) {
@@ -15,16 +16,21 @@ func PutText(box *tui.Box, content *[]string, txtAreaScroll *tui.ScrollArea, sta
 		box.Remove(0)
 	}
 
-	spaceRe := regexp.MustCompile(`\s+`)
+	// Lines are wrapped here, the scroll area gives labels all the width they ask for.
+	width := txtAreaScroll.Size().X
 
 	for i, txt := range *content {
-		txt = strings.Replace(txt, "\t", "    ", -1) // Replace tabs with 4 spaces
-		txt = spaceRe.ReplaceAllString(txt, " ")     // Collapse multiple spaces to single
+		txt = DisplayLine(txt)
 
-		if i != state.CurrentHighlight {
-			label := tui.NewLabel(txt)
+		if spans := searchSpans(i, txt, state); len(spans) > 0 && i != state.CurrentHighlight {
+			box.Append(searchLine(i, txt, spans, width, state))
+		} else if i != state.CurrentHighlight {
+			label := tui.NewLabel(wrapLine(txt, width))
 			label.SetWordWrap(true)
 			label.SetFocused(true)
+			if style := lineStyle(i, state); style != "" {
+				label.SetStyleName(style)
+			}
 			box.Append(label)
 		} else {
 			wordsList := words.ExtractWords(txt)
@@ -58,6 +64,138 @@ func PutText(box *tui.Box, content *[]string, txtAreaScroll *tui.ScrollArea, sta
 	txtAreaScroll.ScrollToTop()
 }
 
+var spaceRe = regexp.MustCompile(`\s+`)
+
+// DisplayLine is a line of the book as it is shown, which is what searches match.
+func DisplayLine(txt string) string {
+	txt = strings.Replace(txt, "\t", "    ", -1) // Replace tabs with 4 spaces
+	return spaceRe.ReplaceAllString(txt, " ")    // Collapse multiple spaces to single
+}
+
+// wrapLine word wraps txt to width columns, leaving it alone before the first layout.
+func wrapLine(txt string, width int) string {
+	if width <= 0 {
+		return txt
+	}
+	return wordwrap.WrapString(txt, width)
+}
+
+// VisiblePage describes the text area for paging: its height and the rows taken by each line
+// of the book once wrapped by PutText. Before the first layout it falls back to state.Advance
+// unwrapped lines.
+func VisiblePage(txtAreaScroll *tui.ScrollArea, state *model.AppState) navigation.Page {
+	width, height := txtAreaScroll.Size().X, txtAreaScroll.Size().Y
+	if width <= 0 || height <= 0 {
+		return navigation.Page{Height: state.Advance, Rows: func(int) int { return 1 }}
+	}
+	return navigation.Page{Height: height, Rows: func(line int) int {
+		return strings.Count(wrapLine(DisplayLine(state.FileContent[line]), width), "\n") + 1
+	}}
+}
+
+// MovePage pages through the book in reading mode, see navigation.PageDown and PageUp.
+func MovePage(txtArea *tui.Box, txtAreaScroll *tui.ScrollArea, state *model.AppState, move func(*model.AppState, navigation.Page)) {
+	if state.CurrentNavMode != model.ReadingNavigationMode {
+		return
+	}
+	move(state, VisiblePage(txtAreaScroll, state))
+	chunk := GetChunk(&state.FileContent, state.From, state.To)
+	PutText(txtArea, &chunk, txtAreaScroll, state)
+}
+
+// lineStyle returns the style of the i-th line of the chunk being read, if the book has one.
+func lineStyle(i int, state *model.AppState) string {
+	if state.CurrentNavMode == model.ShowReferencesNavigationMode {
+		return ""
+	}
+	return state.LineStyles[state.From+i]
+}
+
+// searchSpans returns where the current search matches the i-th line of the chunk being read.
+func searchSpans(i int, txt string, state *model.AppState) [][2]int {
+	if !state.Search.Active() || state.CurrentNavMode == model.ShowReferencesNavigationMode {
+		return nil
+	}
+	return state.Search.Matcher.FindLine(txt)
+}
+
+// searchLine lays out a line with its search matches highlighted, the current match apart. The
+// line is wrapped to width like the others, each row in its own box.
+func searchLine(i int, txt string, spans [][2]int, width int, state *model.AppState) *tui.Box {
+	// current is the position of the current match among the matches of this line, if here.
+	current := -1
+	line := state.From + i
+	if matches := state.Search.Matches; state.Search.Current < len(matches) && matches[state.Search.Current].Line == line {
+		current = 0
+		for k := state.Search.Current - 1; k >= 0 && matches[k].Line == line; k-- {
+			current++
+		}
+	}
+
+	style := lineStyle(i, state)
+	lineBox := tui.NewVBox()
+	for _, row := range searchRows(txt, spans, width) {
+		rowBox := tui.NewHBox()
+		for _, segment := range row {
+			label := tui.NewLabel(segment.text)
+			switch {
+			case segment.match < 0:
+				if style != "" {
+					label.SetStyleName(style)
+				}
+			case segment.match == current:
+				label.SetStyleName("searchcurrent")
+			default:
+				label.SetStyleName("searchhighlight")
+			}
+			rowBox.Append(label)
+		}
+		rowBox.Append(tui.NewSpacer())
+		lineBox.Append(rowBox)
+	}
+	return lineBox
+}
+
+// segment is a piece of a row of a line with search matches, match is the position of the
+// match it belongs to or -1 outside of them.
+type segment struct {
+	text  string
+	match int
+}
+
+// searchRows wraps txt to width as wrapLine does and cuts each row where the matches in spans
+// begin and end, a match wrapped over two rows being split between them.
+func searchRows(txt string, spans [][2]int, width int) [][]segment {
+	var rows [][]segment
+	start := 0
+	for _, text := range strings.Split(wrapLine(txt, width), "\n") {
+		end := start + len(text)
+		var row []segment
+		last := start
+		appendSegment := func(to, match int) {
+			if to > last {
+				row = append(row, segment{text: txt[last:to], match: match})
+				last = to
+			}
+		}
+		for k, span := range spans {
+			if span[1] <= start || span[0] >= end {
+				continue
+			}
+			appendSegment(max(span[0], start), -1)
+			appendSegment(min(span[1], end), k)
+		}
+		appendSegment(end, -1)
+		if row == nil {
+			// A word longer than the width leaves an empty row before it.
+			row = []segment{{match: -1}}
+		}
+		rows = append(rows, row)
+		start = end
+	}
+	return rows
+}
+
 func GetChunk(content *[]string, from, to int) []string {
 	return (*content)[from:to]
 }
@@ -71,7 +209,8 @@ func MoveTextDown(txtArea *tui.Box, txtAreaScroll *tui.ScrollArea, state *model.
 	case model.VocabularyNavigationMode:
 		return // Disable scrolling in table mode
 	// In these modes we don't want to scroll the text area
-	case model.AnalyzeAndFilterReferencesNavigationMode, model.GotoNavigationMode:
+	case model.AnalyzeAndFilterReferencesNavigationMode, model.GotoNavigationMode,
+		model.TOCNavigationMode, model.BookmarksNavigationMode, model.JumpsNavigationMode, model.DefinitionMode, model.ReviewMode:
 		return
 	default:
 		navigation.UpdateRangesDown(state)
@@ -91,7 +230,8 @@ func MoveTextUp(txtArea *tui.Box, txtAreaScroll *tui.ScrollArea, state *model.Ap
 		chunk = GetChunk(&state.References, state.FromForReferences, state.ToReferences)
 	case model.VocabularyNavigationMode:
 		return // Disable scrolling in table mode
-	case model.AnalyzeAndFilterReferencesNavigationMode, model.GotoNavigationMode:
+	case model.AnalyzeAndFilterReferencesNavigationMode, model.GotoNavigationMode,
+		model.TOCNavigationMode, model.BookmarksNavigationMode, model.JumpsNavigationMode, model.DefinitionMode, model.ReviewMode:
 		return
 	default:
 		navigation.UpdateRangesUp(state)
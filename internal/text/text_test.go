@@ -0,0 +1,32 @@
+package text
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchRows(t *testing.T) {
+	txt := "En un lugar de la Mancha, de cuyo nombre no quiero acordarme"
+	// "Mancha, de" is wrapped over two rows.
+	spans := [][2]int{{3, 5}, {18, 28}}
+
+	rows := searchRows(txt, spans, 26)
+	want := [][]segment{
+		{{"En ", -1}, {"un", 0}, {" lugar de la ", -1}, {"Mancha, ", 1}},
+		{{"de", 1}, {" cuyo nombre no quiero ", -1}},
+		{{"acordarme", -1}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got=[%+v], want=[%+v]", rows, want)
+	}
+	if got, want := len(rows), strings.Count(wrapLine(txt, 26), "\n")+1; got != want {
+		t.Errorf("got=[%d], want=[%d] rows", got, want)
+	}
+
+	// Before the first layout the line is not wrapped.
+	rows = searchRows(txt, spans, 0)
+	if len(rows) != 1 {
+		t.Errorf("got=[%d], want=[%d] rows", len(rows), 1)
+	}
+}
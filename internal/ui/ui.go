@@ -2,6 +2,7 @@ package ui
 
 import (
 	"textreader/internal/model"
+	"unicode/utf8"
 
 	"github.com/marcusolsson/tui-go"
 )
@@ -22,13 +23,120 @@ func NewInputCommandBox(input *tui.Entry) *tui.Box {
 	return inputCommandBox
 }
 
-func AddGotoWidget(box *tui.Box, state *model.AppState) {
-	gotoInput := tui.NewTextEdit()
-	gotoInput.SetText("Go To line: ")
+// AddGotoWidget opens the goto prompt. onSubmit is called with the target typed when Enter
+// is pressed, see navigation.ParseGoto for what it can be.
+func AddGotoWidget(box *tui.Box, state *model.AppState, onSubmit func(target string)) {
+	gotoInput := tui.NewEntry()
 	gotoInput.SetFocused(true)
-	gotoInput.OnTextChanged(func(entry *tui.TextEdit) {
-		state.GotoLine = entry.Text()
+	gotoInput.SetSizePolicy(tui.Expanding, tui.Maximum)
+	gotoInput.OnSubmit(func(entry *tui.Entry) {
+		onSubmit(entry.Text())
 	})
-	box.Append(gotoInput)
+
+	gotoBox := tui.NewHBox(tui.NewLabel("Go To: "), gotoInput)
+	gotoBox.SetBorder(true)
+	gotoBox.SetTitle("line, 50%, +200, -30, ch 12, p 143 or a bookmark")
+	gotoBox.SetSizePolicy(tui.Expanding, tui.Maximum)
+	box.Append(gotoBox)
 	state.CurrentNavMode = model.GotoNavigationMode
 }
+
+// AddSearchWidget opens the search prompt. onChanged is called with the query as it is typed
+// and onSubmit when Enter is pressed.
+func AddSearchWidget(box *tui.Box, state *model.AppState, onChanged func(query string), onSubmit func()) {
+	searchInput := tui.NewEntry()
+	searchInput.SetFocused(true)
+	searchInput.SetSizePolicy(tui.Expanding, tui.Maximum)
+	searchInput.OnChanged(func(entry *tui.Entry) {
+		onChanged(entry.Text())
+	})
+	searchInput.OnSubmit(func(*tui.Entry) {
+		onSubmit()
+	})
+
+	searchBox := tui.NewHBox(tui.NewLabel("/"), searchInput)
+	searchBox.SetBorder(true)
+	searchBox.SetTitle("Search (Tab changes the mode)")
+	searchBox.SetSizePolicy(tui.Expanding, tui.Maximum)
+	box.Append(searchBox)
+	state.CurrentNavMode = model.SearchNavigationMode
+}
+
+// AddBookmarkNameWidget opens the prompt to name a bookmark, filled with name. onSubmit is
+// called with the name typed when Enter is pressed.
+func AddBookmarkNameWidget(box *tui.Box, state *model.AppState, name string, onSubmit func(name string)) {
+	nameInput := tui.NewEntry()
+	nameInput.SetText(name)
+	nameInput.SetFocused(true)
+	nameInput.SetSizePolicy(tui.Expanding, tui.Maximum)
+	nameInput.OnSubmit(func(entry *tui.Entry) {
+		onSubmit(entry.Text())
+	})
+
+	nameBox := tui.NewHBox(nameInput)
+	nameBox.SetBorder(true)
+	nameBox.SetTitle("Bookmark name")
+	nameBox.SetSizePolicy(tui.Expanding, tui.Maximum)
+	box.Append(nameBox)
+	state.CurrentNavMode = model.BookmarkNameNavigationMode
+}
+
+// KeyBox is a box that dispatches rune keys case-sensitively before its children see them.
+// tui.UI keybindings ignore case, so "n" and "N" can't have different actions there.
+type KeyBox struct {
+	*tui.Box
+	runes    map[rune]func() bool
+	afterKey []func()
+}
+
+func NewKeyBox(box *tui.Box) *KeyBox {
+	return &KeyBox{Box: box, runes: make(map[rune]func() bool)}
+}
+
+// HandleRune sets the handler of r. When the handler returns true the key is consumed and
+// the children of the box (e.g. a focused entry) don't receive it.
+func (b *KeyBox) HandleRune(r rune, fn func() bool) {
+	b.runes[r] = fn
+}
+
+// AfterKey adds a function called once every key has been handled, by keybindings and widgets.
+func (b *KeyBox) AfterKey(fn func()) {
+	b.afterKey = append(b.afterKey, fn)
+}
+
+func (b *KeyBox) OnKeyEvent(ev tui.KeyEvent) {
+	defer func() {
+		for _, fn := range b.afterKey {
+			fn()
+		}
+	}()
+	if ev.Key == tui.KeyRune && ev.Modifiers&tui.ModAlt == 0 {
+		if fn, ok := b.runes[ev.Rune]; ok && fn() {
+			return
+		}
+	}
+	b.Box.OnKeyEvent(ev)
+}
+
+// typingAwareUI ignores single character keybindings while the user types in a prompt, so
+// typing "s" in a search doesn't save the progress.
+type typingAwareUI struct {
+	tui.UI
+	typing func() bool
+}
+
+func NewTypingAwareUI(u tui.UI, typing func() bool) tui.UI {
+	return &typingAwareUI{UI: u, typing: typing}
+}
+
+func (u *typingAwareUI) SetKeybinding(seq string, fn func()) {
+	if utf8.RuneCountInString(seq) != 1 {
+		u.UI.SetKeybinding(seq, fn)
+		return
+	}
+	u.UI.SetKeybinding(seq, func() {
+		if !u.typing() {
+			fn()
+		}
+	})
+}
@@ -3,8 +3,11 @@ package utils
 import (
 	"fmt"
 	"os/exec"
+	"textreader/internal/chapters"
 	"textreader/internal/model"
 	"textreader/internal/progress"
+	"textreader/internal/review"
+	"textreader/internal/stats"
 	"time"
 )
 
@@ -14,7 +17,7 @@ func Check(err error) {
 	}
 }
 
-func Paginate(x []string, skip, size int) []string {
+func Paginate[T any](x []T, skip, size int) []T {
 	if skip > len(x) {
 		skip = len(x)
 	}
@@ -30,10 +33,10 @@ func Paginate(x []string, skip, size int) []string {
 // TODO: check if we can move these two to a different package
 func GetStatusInformation(state *model.AppState) string {
 	if !state.ToggleShowStatus {
-		return ""
+		return searchInformation(state)
 	}
 
-	percent := progress.GetPercentage(state.To, &state.FileContent)
+	percent := progress.GetBookPercentage(state.To, state.BodyStart, state.BodyEnd)
 	if int(percent) > state.CurrentPercentage {
 		state.CurrentPercentage = int(percent)
 		now := time.Now()
@@ -42,13 +45,105 @@ func GetStatusInformation(state *model.AppState) string {
 	}
 
 	if state.PercentagePointStats {
-		return fmt.Sprintf(".   %d of %d lines (%.3f%%) [%d lines To next percentage point]                    ",
-			state.To,
-			len(state.FileContent), percent, progress.LinesToChangePercentagePoint(state.To, len(state.FileContent)))
+		return fmt.Sprintf(".   %s%d of %d lines (%.3f%%)%s%s%s%s%s%s [%d lines To next percentage point]                    ",
+			bookTitle(state), state.To,
+			len(state.FileContent), percent, pageInformation(state), chapterInformation(state), timeLeftInformation(state), goalInformation(state), reviewInformation(state), searchInformation(state), progress.LinesToChangePercentagePoint(state.To-state.BodyStart, state.BodyEnd-state.BodyStart))
 	}
-	return fmt.Sprintf(".   %d of %d lines (%.3f%%)                                            ",
-		state.To, len(state.FileContent), percent)
+	return fmt.Sprintf(".   %s%d of %d lines (%.3f%%)%s%s%s%s%s%s                                            ",
+		bookTitle(state), state.To, len(state.FileContent), percent, pageInformation(state), chapterInformation(state), timeLeftInformation(state), goalInformation(state), reviewInformation(state), searchInformation(state))
+
+}
 
+func bookTitle(state *model.AppState) string {
+	switch {
+	case state.Metadata.Title == "":
+		return ""
+	case state.Metadata.Author == "":
+		return fmt.Sprintf("%s | ", state.Metadata.Title)
+	default:
+		return fmt.Sprintf("%s, %s | ", state.Metadata.Title, state.Metadata.Author)
+	}
+}
+
+// pageInformation tells the page shown at the top of the screen, for books with pages.
+func pageInformation(state *model.AppState) string {
+	if len(state.PageBreaks) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" page %d of %d", progress.GetPageNumber(state.From, state.PageBreaks), len(state.PageBreaks))
+}
+
+// chapterInformation tells the chapter shown at the top of the screen and how much of it
+// has been read.
+func chapterInformation(state *model.AppState) string {
+	current := chapters.Current(state.Chapters, state.From)
+	if current < 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | %s (%.0f%%)", state.Chapters[current].Title,
+		chapters.Percentage(state.Chapters, current, state.From, state.BodyEnd))
+}
+
+// timeLeftInformation tells how long the chapter and the book will take to finish at the
+// speed read so far.
+func timeLeftInformation(state *model.AppState) string {
+	speed := stats.ReadingSpeed(state.AllSessions(time.Now()))
+	if !speed.Known() {
+		return ""
+	}
+	left := stats.EstimateTimeLeft(state, speed)
+	if !left.InChapter {
+		return fmt.Sprintf(" | %s left", stats.FormatDuration(left.Book))
+	}
+	return fmt.Sprintf(" | %s left in chapter, %s in book", stats.FormatDuration(left.Chapter), stats.FormatDuration(left.Book))
+}
+
+// goalInformation tells how far along the daily goal is and for how many days in a row it
+// has been reached.
+func goalInformation(state *model.AppState) string {
+	if !state.Goal.Set() {
+		return ""
+	}
+	now := time.Now()
+	sessions := stats.GoalSessions(state, now)
+	info := fmt.Sprintf(" | goal %s", stats.FormatGoalProgress(state.Goal, stats.GoalProgress(sessions, state.Goal, now)))
+	if streak := stats.Streak(sessions, state.Goal, now); streak > 0 {
+		info += ", streak " + Days(streak)
+	}
+	return info
+}
+
+// reviewInformation tells how many words of every book are to be reviewed.
+func reviewInformation(state *model.AppState) string {
+	due := review.CountDue(state.Vocabulary, time.Now()) + state.DueInOtherBooks
+	if due == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | %d words to review", due)
+}
+
+// Days writes a number of days, "1 day" or "4 days".
+func Days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// searchInformation tells how the current search went, or its mode while the query is typed.
+func searchInformation(state *model.AppState) string {
+	search := state.Search
+	switch {
+	case search.Err != "":
+		return " | " + search.Err
+	case search.Active() && len(search.Matches) == 0:
+		return fmt.Sprintf(" | no matches for %q (%s)", search.Query, search.Mode)
+	case search.Active():
+		return fmt.Sprintf(" | match %d of %d for %q (%s)", search.Current+1, len(search.Matches), search.Query, search.Mode)
+	case state.CurrentNavMode == model.SearchNavigationMode:
+		return fmt.Sprintf(" | search (%s)", search.Mode)
+	}
+	return ""
 }
 
 func GetSavedStatusInformation(fileName string, state *model.AppState) string {
@@ -36,3 +36,46 @@ func Contains(words []string, word string) bool {
 	}
 	return false
 }
+
+// maxSentenceLines is how many lines before and after the word a sentence is looked for in.
+const maxSentenceLines = 5
+
+// Sentence returns the sentence the n-th word of lines[line] is part of. Sentences may span
+// several lines but not paragraphs.
+func Sentence(lines []string, line, n int) string {
+	if line < 0 || line >= len(lines) || len(ExtractWords(lines[line])) == 0 {
+		return ""
+	}
+	first, last := line, line
+	for first > 0 && first > line-maxSentenceLines && strings.TrimSpace(lines[first-1]) != "" {
+		first--
+	}
+	for last+1 < len(lines) && last < line+maxSentenceLines && strings.TrimSpace(lines[last+1]) != "" {
+		last++
+	}
+
+	var paragraph []string
+	position := 0
+	for i := first; i <= last; i++ {
+		fields := ExtractWords(lines[i])
+		if i == line {
+			position = len(paragraph) + max(0, min(n, len(fields)-1))
+		}
+		paragraph = append(paragraph, fields...)
+	}
+
+	start, end := position, position
+	for start > 0 && !endsSentence(paragraph[start-1]) {
+		start--
+	}
+	for end < len(paragraph)-1 && !endsSentence(paragraph[end]) {
+		end++
+	}
+	return strings.Join(paragraph[start:end+1], " ")
+}
+
+func endsSentence(word string) bool {
+	word = strings.TrimRight(word, `"')]»”’`)
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") ||
+		strings.HasSuffix(word, "?") || strings.HasSuffix(word, "…")
+}
@@ -79,3 +79,35 @@ func Test_sanitizeFileName(t *testing.T) {
 		}
 	}
 }
+
+func TestSentence(t *testing.T) {
+	lines := []string{
+		"En un lugar de la Mancha, de cuyo nombre no quiero",
+		"acordarme, no ha mucho tiempo que vivía un hidalgo. Tenía",
+		"una ama que pasaba de los cuarenta, y una sobrina",
+		"que no llegaba a los veinte.",
+		"",
+		"«¿Quién eres?» preguntó.",
+	}
+
+	type test struct {
+		line, n int
+		want    string
+	}
+
+	tests := []test{
+		{line: 0, n: 3, want: "En un lugar de la Mancha, de cuyo nombre no quiero acordarme, no ha mucho tiempo que vivía un hidalgo."},
+		{line: 1, n: 9, want: "Tenía una ama que pasaba de los cuarenta, y una sobrina que no llegaba a los veinte."},
+		{line: 3, n: 99, want: "Tenía una ama que pasaba de los cuarenta, y una sobrina que no llegaba a los veinte."},
+		{line: 5, n: 1, want: "«¿Quién eres?»"},
+		{line: 5, n: 2, want: "preguntó."},
+		{line: 4, n: 0, want: ""},
+		{line: 10, n: 0, want: ""},
+	}
+
+	for _, tc := range tests {
+		if got := Sentence(lines, tc.line, tc.n); got != tc.want {
+			t.Errorf("Sentence(%d, %d) = %q, want %q", tc.line, tc.n, got, tc.want)
+		}
+	}
+}
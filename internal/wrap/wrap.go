@@ -0,0 +1,33 @@
+// Package wrap breaks paragraphs into lines for the formats that don't have their own.
+package wrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Paragraph splits a paragraph into lines of at most width runes, breaking on whitespace.
+// Words longer than width are kept whole on their own line.
+func Paragraph(paragraph string, width int) []string {
+	lines := make([]string, 0)
+	var sb strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(paragraph) {
+		wordLen := utf8.RuneCountInString(word)
+		if lineLen > 0 && lineLen+1+wordLen > width {
+			lines = append(lines, sb.String())
+			sb.Reset()
+			lineLen = 0
+		}
+		if lineLen > 0 {
+			sb.WriteString(" ")
+			lineLen++
+		}
+		sb.WriteString(word)
+		lineLen += wordLen
+	}
+	if lineLen > 0 {
+		lines = append(lines, sb.String())
+	}
+	return lines
+}
@@ -4,12 +4,18 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"textreader/internal/chapters"
+	"textreader/internal/config"
+	"textreader/internal/dictionary"
 	"textreader/internal/file"
+	"textreader/internal/gutenberg"
 	"textreader/internal/keybindings"
+	"textreader/internal/markup"
 	"textreader/internal/model"
 	"textreader/internal/progress"
 	"textreader/internal/references"
+	"textreader/internal/review"
+	"textreader/internal/stats"
 	"textreader/internal/terminal"
 	"textreader/internal/text"
 	"textreader/internal/ui"
@@ -20,10 +26,35 @@ import (
 )
 
 func main() {
-	fileFlag := flag.String("file", "", "File to open")
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		if err := runStats(os.Args[2:], os.Stdout); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export-vocab" {
+		cfg, err := config.Load(config.Path())
+		if err == nil {
+			err = runExportVocab(os.Args[2:], os.Stdout, cfg.VocabExport)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fileFlag := flag.String("file", "", "File to open, \"-\" reads from standard input")
+	stripGutenbergFlag := flag.Bool("strip-gutenberg", true, "Leave the Project Gutenberg header and license out of percentages and references")
+	encodingFlag := flag.String("encoding", file.AutoEncoding, "Encoding of text files (auto, utf-8, utf-16le, utf-16be, iso-8859-1, windows-1252, windows-1251)")
+	formatFlag := flag.String("format", "", "Format of the file (pdf, epub, odt, docx, fb2, html, markdown, text or a configured converter), guessed when empty")
 	flag.Parse()
 	state := model.NewAppState()
 	state.FileToOpen = *fileFlag
+	state.Encoding = *encodingFlag
+	state.Format = *formatFlag
+	state.StripGutenberg = *stripGutenbergFlag
 
 	if err := run(state); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -33,6 +64,10 @@ func main() {
 
 func run(state *model.AppState) error {
 	fileName := state.FileToOpen
+	if fileName == "" && !terminal.IsStdinTerminal() {
+		fileName = model.StdinFileName
+		state.FileToOpen = fileName
+	}
 	if fileName == "" {
 		return fmt.Errorf("missing file to read")
 	}
@@ -47,31 +82,62 @@ func run(state *model.AppState) error {
 		return fmt.Errorf("failed to load banned words: %w", err)
 	}
 
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	registry := file.NewRegistry()
+	if err := config.RegisterConverters(registry, cfg.Converters); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	dictionaries := cfg.Dictionaries
+	dictionaries.Dir = cfg.DictionariesDir()
+	// The dictionaries are optional, the book opens without them.
+	library, err := dictionary.Open(dictionaries)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		library = &dictionary.Library{}
+	}
+
 	state.Sidebar.Append(state.RefsTable)
 	state.Sidebar.Append(state.VocabTable)
+	state.Sidebar.Append(state.TOCTable)
+	state.Sidebar.Append(state.BookmarksTable)
+	state.Sidebar.Append(state.JumpsTable)
 
-	absoluteFilePath, err := filepath.Abs(fileName)
+	doc, err := loadDocument(fileName, registry, state)
 	if err != nil {
-		return fmt.Errorf("failed to resolve file path: %w", err)
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	state.FileContent, state.Chapters, state.Metadata = doc.Lines, doc.Chapters, doc.Metadata
+	if doc.Styles != nil {
+		state.LineStyles = doc.Styles
 	}
+	if doc.PageBreaks != nil {
+		state.PageBreaks = doc.PageBreaks
+	}
+	state.BodyStart, state.BodyEnd = 0, len(state.FileContent)
+	if state.StripGutenberg {
+		state.BodyStart, state.BodyEnd, _ = gutenberg.FindBody(state.FileContent)
+	}
+	if len(state.Chapters) == 0 {
+		state.Chapters = chapters.Detect(state.FileContent, state.BodyStart, state.BodyEnd, cfg.Chapters)
+	}
+	state.WordCounts = stats.CountWords(state.FileContent)
 
-	latestFile, err := file.GetFileNameFromLatest(absoluteFilePath, state)
+	latestFile, err := file.GetFileNameFromLatest(fileName, state)
 	if err != nil {
 		return fmt.Errorf("failed to load latest file: %w", err)
 	}
 
 	state.From, state.To, fileName = latestFile.From, latestFile.To, latestFile.FileName
-	// state.FromVocabulary = 0
-
-	txtFile, err := os.Open(fileName)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+	state.Goal = cfg.Goal
+	if err := loadOtherBooks(fileName, state); err != nil {
+		return err
 	}
-	defer txtFile.Close()
-
-	state.FileContent, err = file.ReadLines(txtFile)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+	// state.FromVocabulary = 0
+	if state.From == 0 {
+		state.From = state.BodyStart
 	}
 
 	state.Advance = terminal.CalculateTerminalHeight()
@@ -81,7 +147,8 @@ func run(state *model.AppState) error {
 	}
 
 	state.StartTime = time.Now()
-	state.CurrentPercentage = int(progress.GetPercentage(state.To, &state.FileContent))
+	state.Session = model.ReadingSession{Start: state.StartTime}
+	state.CurrentPercentage = int(progress.GetBookPercentage(state.To, state.BodyStart, state.BodyEnd))
 	state.FromForVocabulary = 0
 	state.ToForVocabulary = len(state.Vocabulary)
 
@@ -101,12 +168,14 @@ func run(state *model.AppState) error {
 	chunk := text.GetChunk(&state.FileContent, state.From, state.To)
 	text.PutText(txtArea, &chunk, txtAreaScroll, state)
 
-	root := tui.NewHBox(txtReader, state.Sidebar)
+	root := ui.NewKeyBox(tui.NewHBox(txtReader, state.Sidebar))
 
-	tuiUI, err := tui.New(root)
+	rawUI, err := tui.New(root)
 	if err != nil {
 		return fmt.Errorf("failed to initialize UI: %w", err)
 	}
+	// Letter shortcuts must not fire while a search query is being typed.
+	tuiUI := ui.NewTypingAwareUI(rawUI, func() bool { return state.CurrentNavMode.TakesText() })
 
 	theme := tui.NewTheme()
 	theme.SetStyle("label.highlight", tui.Style{
@@ -119,6 +188,25 @@ func run(state *model.AppState) error {
 		Bold:      tui.DecorationOn,
 		Underline: tui.DecorationOn,
 	})
+	theme.SetStyle("label."+markup.HeadingStyle, tui.Style{
+		Fg:   tui.ColorYellow,
+		Bold: tui.DecorationOn,
+	})
+	theme.SetStyle("label."+markup.FootnoteStyle, tui.Style{
+		Fg: tui.ColorCyan,
+	})
+	theme.SetStyle("label."+markup.PageBreakStyle, tui.Style{
+		Fg: tui.ColorBlue,
+	})
+	theme.SetStyle("label.searchhighlight", tui.Style{
+		Fg: tui.ColorBlack,
+		Bg: tui.ColorYellow,
+	})
+	theme.SetStyle("label.searchcurrent", tui.Style{
+		Fg:   tui.ColorWhite,
+		Bg:   tui.ColorRed,
+		Bold: tui.DecorationOn,
+	})
 	theme.SetStyle("table.cell.selected", tui.Style{
 		Fg: tui.ColorBlack,
 		Bg: tui.ColorYellow,
@@ -127,28 +215,40 @@ func run(state *model.AppState) error {
 
 	keybindings.AddUpDownKeyBindings(txtArea, tuiUI, inputCommand, txtAreaScroll, state)
 	keybindings.AddHighlightUpDownKeyBindings(txtArea, tuiUI, inputCommand, txtAreaScroll, state)
+	keybindings.AddPagingKeyBindings(txtArea, tuiUI, inputCommand, txtAreaScroll, state)
 	keybindings.AddWordLeftRightKeyBindings(txtArea, tuiUI, inputCommand, txtAreaScroll, state)
 	keybindings.AddCopyWordKeyBinding(tuiUI, inputCommand, state)
-	keybindings.AddGotoKeyBinding(tuiUI, txtReader, state)
+	keybindings.AddGotoKeyBinding(root, inputCommand, txtReader, txtArea, txtAreaScroll, state)
 	keybindings.AddShowStatusKeyBinding(tuiUI, inputCommand, state)
 	keybindings.AddNewNoteKeyBinding(tuiUI, txtArea, inputCommand, fileName, txtAreaScroll, state)
-	keybindings.AddCloseGotoBinding(tuiUI, inputCommand, txtReader, txtArea, txtAreaScroll, state)
 	keybindings.AddSaveStatusKeyBinding(tuiUI, fileName, inputCommand, state)
 	keybindings.AddShowReferencesKeyBinding(tuiUI, txtArea, txtAreaScroll, state)
 	keybindings.AddAnalyzeAndFilterReferencesKeyBinding(tuiUI, state)
 	keybindings.AddPercentageKeyBindings(tuiUI, inputCommand, state)
-	keybindings.AddCloseApplicationKeyBinding(tuiUI, txtArea, txtReader, txtAreaScroll, state)
+	keybindings.AddCloseApplicationKeyBinding(tuiUI, txtArea, txtReader, inputCommand, txtAreaScroll, state)
 	keybindings.AddReferencesNavigationKeyBindings(tuiUI, state)
 	keybindings.AddSaveQuoteKeyBindings(tuiUI, fileName, txtArea, inputCommand, txtAreaScroll, state)
 	keybindings.AddOnSelectedReference(state)
 	keybindings.AddShowMinutesTakenToReachPercentagePointKeyBinding(tuiUI, txtReader, state)
 	keybindings.AddShowHelpKeyBinding(tuiUI, txtReader, state)
 	keybindings.AddOpenRAEWebSite(tuiUI, inputCommand)
+	keybindings.AddLookUpKeyBindings(tuiUI, root, txtReader, inputCommand, txtAreaScroll, library, dictionaries.Dir, cfg.DictServer, state)
+	keybindings.AddReviewKeyBindings(tuiUI, txtReader, inputCommand, txtAreaScroll, fileName, library, state)
 	keybindings.AddSaveVocabularyKeyBinding(tuiUI, fileName, inputCommand, state)
 	keybindings.AddVocabularyNavigationKeyBindings(tuiUI, state, inputCommand)
 	keybindings.AddOnSelectedVocabulary(state)
 	keybindings.AddShowVocabularyKeyBinding(tuiUI, txtReader, txtArea, inputCommand, txtAreaScroll, state)
 	keybindings.AddDeleteVocabularyKeyBinding(tuiUI, inputCommand, state)
+	keybindings.AddExportVocabularyKeyBinding(tuiUI, fileName, inputCommand, cfg.VocabExport, state)
+	keybindings.AddShowTOCKeyBinding(tuiUI, inputCommand, state)
+	keybindings.AddTOCNavigationKeyBindings(tuiUI, state)
+	keybindings.AddOnSelectedTOC(txtArea, inputCommand, txtAreaScroll, state)
+	keybindings.AddBookmarkKeyBindings(tuiUI, root, fileName, txtReader, txtArea, inputCommand, txtAreaScroll, state)
+	keybindings.AddSearchKeyBindings(tuiUI, root, txtReader, txtArea, inputCommand, txtAreaScroll, state)
+	keybindings.AddJumpKeyBindings(tuiUI, root, txtArea, inputCommand, txtAreaScroll, state)
+	// Time between keys counts as reading, unless it is long enough to be a pause.
+	root.AfterKey(func() { stats.RecordActivity(&state.Session, time.Now()) })
+	keybindings.AddGoalNotice(root, inputCommand, state)
 
 	inputCommand.SetText(utils.GetStatusInformation(state))
 
@@ -157,5 +257,46 @@ func run(state *model.AppState) error {
 	if err := tuiUI.Run(); err != nil {
 		return fmt.Errorf("failed to run UI: %w", err)
 	}
+	if err := file.SaveSession(fileName, state); err != nil {
+		return fmt.Errorf("failed to save reading statistics: %w", err)
+	}
+	return nil
+}
+
+// loadOtherBooks loads what the status line needs of the books other than fileName: their
+// reading sessions, which count toward the daily goal, and their words to review.
+func loadOtherBooks(fileName string, state *model.AppState) error {
+	key, err := file.ProgressKey(fileName, state)
+	if err != nil {
+		return err
+	}
+	entries, err := file.LoadProgress()
+	if err != nil {
+		return fmt.Errorf("failed to load progress: %w", err)
+	}
+	now := time.Now()
+	for entryKey, entry := range entries {
+		if entryKey == key {
+			continue
+		}
+		state.OtherSessions = append(state.OtherSessions, entry.Sessions...)
+		state.DueInOtherBooks += review.CountDue(entry.Vocabulary, now)
+	}
 	return nil
 }
+
+func loadDocument(fileName string, registry *file.Registry, state *model.AppState) (file.Document, error) {
+	opts := file.LoadOptions{Encoding: state.Encoding, Format: state.Format, Registry: registry}
+	if fileName != model.StdinFileName {
+		return file.LoadDocument(fileName, opts)
+	}
+
+	doc, err := file.ReadDocument(os.Stdin, fileName, opts)
+	if err != nil {
+		return file.Document{}, err
+	}
+	if err := terminal.ReopenStdin(); err != nil {
+		return file.Document{}, fmt.Errorf("failed to reopen the terminal: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"textreader/internal/file"
+	"textreader/internal/stats"
+	"time"
+)
+
+// calendarWeeks is how many weeks the calendar of the stats subcommand shows.
+const calendarWeeks = 26
+
+// runStats prints what has been read, by book and overall, from the progress file.
+func runStats(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	jsonFlag := flags.Bool("json", false, "Print the statistics as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	entries, err := file.LoadProgress()
+	if err != nil {
+		return fmt.Errorf("failed to load progress: %w", err)
+	}
+	books := make([]stats.Book, 0, len(entries))
+	for _, entry := range entries {
+		books = append(books, stats.Book{
+			FileName:   entry.FileName,
+			Title:      entry.Title,
+			Author:     entry.Author,
+			Percentage: entry.Percentage,
+			Sessions:   entry.Sessions,
+		})
+	}
+	summary := stats.Summarize(books)
+
+	if *jsonFlag {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summary); err != nil {
+			return fmt.Errorf("failed to write statistics: %w", err)
+		}
+		return nil
+	}
+	if err := stats.WriteSummary(w, summary, time.Now(), calendarWeeks); err != nil {
+		return fmt.Errorf("failed to write statistics: %w", err)
+	}
+	return nil
+}
@@ -10,26 +10,9 @@ import (
 	"textreader/internal/progress"
 	"textreader/internal/text"
 	"textreader/internal/utils"
+	"textreader/internal/wrap"
 )
 
-func Test_getNumberLineGoto(t *testing.T) {
-
-	type test struct {
-		line string
-		want string
-	}
-
-	tests := []test{
-		{line: "hola12_mundo_345", want: "12345"},
-	}
-
-	for _, tc := range tests {
-		if got := progress.GetNumberLineGoto(tc.line); got != tc.want {
-			t.Errorf("expected: %s, got: %s", tc.want, got)
-		}
-	}
-}
-
 func Test_percent(t *testing.T) {
 
 	type test struct {
@@ -65,6 +48,13 @@ func Test_linesToChangePercentagePoint(t *testing.T) {
 	}
 }
 
+func Test_linesToChangePercentagePointEmptyBody(t *testing.T) {
+	// A Gutenberg file with only a header and a license has an empty body.
+	if got := progress.LinesToChangePercentagePoint(0, 0); got != 0 {
+		t.Errorf("got=[%d], want=[%d]", got, 0)
+	}
+}
+
 func TestGetFileToSaveName(t *testing.T) {
 	name := "/getHomeDirectoryPath/leo/code/little-txt-book-reader/lala.xts"
 	if baseFileName := filepath.Base(name); baseFileName != "lala.xts" {
@@ -357,6 +347,69 @@ func Test_getPercentage(t *testing.T) {
 
 }
 
+func Test_wrap(t *testing.T) {
+	type test struct {
+		paragraph string
+		width     int
+		want      []string
+	}
+
+	tests := []test{
+		{paragraph: "anita lava la tina", width: 10, want: []string{"anita lava", "la tina"}},
+		{paragraph: "  En un lugar   de la Mancha ", width: 12, want: []string{"En un lugar", "de la Mancha"}},
+		{paragraph: "supercalifragilistico es", width: 5, want: []string{"supercalifragilistico", "es"}},
+		{paragraph: "", width: 10, want: []string{}},
+	}
+
+	for _, tc := range tests {
+		if got := wrap.Paragraph(tc.paragraph, tc.width); !listsAreEqual(got, tc.want) {
+			t.Errorf("got=[%s], want=[%s]", got, tc.want)
+		}
+	}
+}
+
+func Test_getBookPercentage(t *testing.T) {
+	type test struct {
+		position, bodyStart, bodyEnd int
+		want                         float64
+	}
+
+	tests := []test{
+		{position: 30, bodyStart: 10, bodyEnd: 110, want: 20.0},
+		{position: 5, bodyStart: 10, bodyEnd: 110, want: 0.0},
+		{position: 150, bodyStart: 10, bodyEnd: 110, want: 100.0},
+		{position: 4, bodyStart: 0, bodyEnd: 10, want: 40.0},
+	}
+
+	for _, tc := range tests {
+		if got := progress.GetBookPercentage(tc.position, tc.bodyStart, tc.bodyEnd); got != tc.want {
+			t.Errorf("got=[%f], want=[%f]", got, tc.want)
+		}
+	}
+}
+
+func Test_getPageNumber(t *testing.T) {
+	type test struct {
+		line       int
+		pageBreaks []int
+		want       int
+	}
+
+	tests := []test{
+		{line: 0, pageBreaks: []int{0, 40, 80}, want: 1},
+		{line: 39, pageBreaks: []int{0, 40, 80}, want: 1},
+		{line: 40, pageBreaks: []int{0, 40, 80}, want: 2},
+		{line: 500, pageBreaks: []int{0, 40, 80}, want: 3},
+		{line: 10, pageBreaks: []int{}, want: 0},
+	}
+
+	for _, tc := range tests {
+		if got := progress.GetPageNumber(tc.line, tc.pageBreaks); got != tc.want {
+			t.Errorf("got=[%d], want=[%d]", got, tc.want)
+		}
+	}
+}
+
 func listsAreEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false